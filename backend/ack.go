@@ -0,0 +1,88 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/maxiiot/devicebridge/backend/protocol"
+	"github.com/maxiiot/devicebridge/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+// AckEvent json event published per downlink acknowledgement
+type AckEvent struct {
+	DevEUI     storage.EUI64 `json:"dev_eui"`
+	DownlinkID int64         `json:"downlink_id,omitempty"` // 为空表示未找到对应的下行数据
+	Code       uint8         `json:"code"`
+	Result     uint8         `json:"result"`
+	Status     string        `json:"status"`
+	FCnt       uint32        `json:"fcnt"`
+	ServerTime time.Time     `json:"server_time"`
+}
+
+// handleAcks mark the oldest sent downlink containing the acknowledged function code
+// as acknowledged or failed, and publish ack event
+func handleAcks(conn paho.Client, dev storage.Device, data DataUpPayloadChan, acks []protocol.Ack) {
+	if len(acks) == 0 {
+		return
+	}
+
+	dls, err := storage.GetSentDownlinks(data.DevEUI)
+	if err != nil {
+		log.WithError(err).WithField("device", data.DevEUI).Error("get sent downlinks error")
+	}
+
+	for _, ack := range acks {
+		event := AckEvent{
+			DevEUI:     data.DevEUI,
+			Code:       ack.Code,
+			Result:     ack.Result,
+			Status:     storage.DownlinkStatusAcknowledged,
+			FCnt:       data.FCnt,
+			ServerTime: time.Now(),
+		}
+		errText := ""
+		if !ack.Success() {
+			event.Status = storage.DownlinkStatusFailed
+			errText = fmt.Sprintf("device returns result 0x%02x", ack.Result)
+		}
+
+		for i, dl := range dls {
+			if !hasFunctionCode(dl.Data, ack.Code) {
+				continue
+			}
+			if err := storage.AckDownlink(dl.ID, event.Status, errText); err != nil {
+				log.WithError(err).WithField("id", dl.ID).Error("update downlink ack error")
+			}
+			event.DownlinkID = dl.ID
+			dls = append(dls[:i], dls[i+1:]...)
+			break
+		}
+		if event.DownlinkID == 0 {
+			log.WithField("device", data.DevEUI).Warnf("no sent downlink of ack code 0x%02x", ack.Code)
+		}
+
+		b, err := json.Marshal(event)
+		if err != nil {
+			log.WithError(err).WithField("device", data.DevEUI).Error("marshal ack event error")
+			continue
+		}
+		publish(conn, publishTopic(dev, data, "ack"), retainFields["ack"], string(b))
+	}
+}
+
+// hasFunctionCode returns whether the downlink frame contains function code
+func hasFunctionCode(frame []byte, code uint8) bool {
+	codes, err := protocol.FunctionCodes(frame)
+	if err != nil {
+		return false
+	}
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
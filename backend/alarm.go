@@ -0,0 +1,76 @@
+package backend
+
+import (
+	"time"
+
+	"github.com/maxiiot/devicebridge/backend/protocol"
+	"github.com/maxiiot/devicebridge/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+// decodedAlarms returns alarms of decoded object
+func decodedAlarms(decoded protocol.Decoded) []protocol.Alarm {
+	if a, ok := decoded.Object.(protocol.Alarmer); ok {
+		return a.Alarms()
+	}
+	return nil
+}
+
+// handleAlarms open or count alarm records of decoded alarms,
+// active alarms of device are cleared by normal frame
+func handleAlarms(dev storage.Device, decoded protocol.Decoded, receivedAt time.Time) {
+	a, ok := decoded.Object.(protocol.Alarmer)
+	if !ok {
+		return
+	}
+	alarms := a.Alarms()
+	storeAlarmEvents(dev, alarms, receivedAt)
+
+	for _, alarm := range alarms {
+		raisedAt := alarm.DateTime
+		if raisedAt.IsZero() {
+			raisedAt = receivedAt
+		}
+		record, err := storage.RaiseAlarm(dev.DeviceEUI, dev.ProtocolType, alarm.Name, raisedAt)
+		if err != nil {
+			log.WithError(err).WithField("device", dev.DeviceEUI).Error("raise alarm error")
+			continue
+		}
+		log.WithFields(log.Fields{
+			"device": dev.DeviceEUI,
+			"alarm":  record.Name,
+			"count":  record.Count,
+		}).Info("alarm raised")
+	}
+
+	if len(alarms) == 0 && a.Normal() {
+		n, err := storage.ClearAlarms(dev.DeviceEUI, receivedAt)
+		if err != nil {
+			log.WithError(err).WithField("device", dev.DeviceEUI).Error("clear alarms error")
+		} else if n > 0 {
+			log.WithField("device", dev.DeviceEUI).WithField("count", n).Info("alarms cleared")
+		}
+	}
+}
+
+// storeAlarmEvents store decoded alarms as device alarm events,
+// alarms without time are stored at receive time
+func storeAlarmEvents(dev storage.Device, alarms []protocol.Alarm, receivedAt time.Time) {
+	events := make([]storage.AlarmEvent, 0, len(alarms))
+	for _, a := range alarms {
+		e := storage.AlarmEvent{
+			DeviceEUI:    dev.DeviceEUI,
+			ProtocolType: dev.ProtocolType,
+			Name:         a.Name,
+			SampleTime:   a.DateTime,
+			ReceivedAt:   receivedAt,
+		}
+		if e.SampleTime.IsZero() {
+			e.SampleTime = receivedAt
+		}
+		events = append(events, e)
+	}
+	if err := storage.CreateAlarmEvents(events); err != nil {
+		log.WithError(err).WithField("device", dev.DeviceEUI).Error("store alarm events error")
+	}
+}
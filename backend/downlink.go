@@ -0,0 +1,29 @@
+package backend
+
+import (
+	"github.com/maxiiot/devicebridge/storage"
+)
+
+// DownlinkPayload downlink sent to lora network server
+type DownlinkPayload struct {
+	ApplicationID int64
+	DevEUI        storage.EUI64
+	Confirmed     bool
+	FPort         uint8
+	Data          []byte
+}
+
+// DownlinkTxPayload lora-app-server tx payload
+type DownlinkTxPayload struct {
+	DevEUI    storage.EUI64 `json:"devEUI"`
+	Confirmed bool          `json:"confirmed"`
+	FPort     uint8         `json:"fPort"`
+	Data      []byte        `json:"data"`
+}
+
+// DownlinkSender send downlink to lora network server
+type DownlinkSender interface {
+	SendDownlink(DownlinkPayload) error
+	// IsConnected 是否已连接,未连接时下行数据保持pending
+	IsConnected() bool
+}
@@ -0,0 +1,163 @@
+package backend
+
+import (
+	"sync"
+	"time"
+
+	"github.com/maxiiot/devicebridge/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+// DefaultDedupWindow default window of dropping uplinks with the same frame counter
+const DefaultDedupWindow = time.Minute
+
+// maxFCntReorder max backward step of frame counter treated as late frame,
+// larger steps or steps after dedup window are counter resets
+const maxFCntReorder = 16
+
+// fcntRestartMax backward step to frame counter <= fcntRestartMax is counter reset, device restarts from 0 or 1
+const fcntRestartMax = 1
+
+// fcntState last frame counter of device and frame counters seen within dedup window
+type fcntState struct {
+	fcnt   uint32
+	seenAt time.Time
+	seen   map[uint32]time.Time
+}
+
+// minSeen returns the smallest frame counter seen within dedup window
+func (s *fcntState) minSeen() uint32 {
+	min := s.fcnt
+	for n := range s.seen {
+		if n < min {
+			min = n
+		}
+	}
+	return min
+}
+
+// fcntResult classification of uplink frame counter
+type fcntResult struct {
+	duplicate bool
+	late      bool
+	lost      int64 // 帧计数跳变推算的丢包数,乱序补上的帧为-1
+	reset     bool
+}
+
+var (
+	fcntMu      sync.Mutex
+	fcntStates  = make(map[storage.EUI64]*fcntState)
+	fcntPruned  time.Time
+	dedupWindow = DefaultDedupWindow
+)
+
+// SetDedupWindow set window of dropping duplicate uplinks, <=0 means default
+func SetDedupWindow(window time.Duration) {
+	if window <= 0 {
+		window = DefaultDedupWindow
+	}
+	fcntMu.Lock()
+	dedupWindow = window
+	fcntMu.Unlock()
+}
+
+// checkFCnt classify frame counter of uplink against frame counters seen within dedup window,
+// the last frame counter is loaded from device when not tracked in memory yet.
+// backward steps below every frame counter seen or to near 0 are counter resets
+func checkFCnt(dev storage.Device, fcnt uint32, seenAt time.Time) fcntResult {
+	fcntMu.Lock()
+	defer fcntMu.Unlock()
+	pruneFCntStates(seenAt)
+
+	state, ok := fcntStates[dev.DeviceEUI]
+	if !ok && dev.LastFCnt != nil && dev.LastSeenAt != nil {
+		state, ok = &fcntState{
+			fcnt:   uint32(*dev.LastFCnt),
+			seenAt: *dev.LastSeenAt,
+			seen:   map[uint32]time.Time{uint32(*dev.LastFCnt): *dev.LastSeenAt},
+		}, true
+	}
+	if !ok {
+		fcntStates[dev.DeviceEUI] = &fcntState{
+			fcnt:   fcnt,
+			seenAt: seenAt,
+			seen:   map[uint32]time.Time{fcnt: seenAt},
+		}
+		return fcntResult{}
+	}
+	fcntStates[dev.DeviceEUI] = state
+
+	for n, t := range state.seen {
+		if seenAt.Sub(t) > dedupWindow {
+			delete(state.seen, n)
+		}
+	}
+
+	var res fcntResult
+	// 回退到接近0的帧计数为设备重启,不作为窗口内重复帧丢弃
+	restart := fcnt < state.fcnt && fcnt <= fcntRestartMax
+	if _, dup := state.seen[fcnt]; dup && !restart {
+		res.duplicate = true
+		return res
+	}
+
+	switch {
+	case fcnt > state.fcnt:
+		res.lost = int64(fcnt - state.fcnt - 1)
+	case !restart && fcnt < state.fcnt && fcnt > state.minSeen() &&
+		state.fcnt-fcnt <= maxFCntReorder && seenAt.Sub(state.seenAt) <= dedupWindow:
+		// 乱序到达的帧,不回退帧计数,补上之前计为丢失的帧
+		res.late = true
+		res.lost = -1
+		state.seen[fcnt] = seenAt
+		return res
+	default:
+		// 帧计数回退,设备重启
+		res.reset = true
+		state.seen = make(map[uint32]time.Time)
+	}
+	state.fcnt = fcnt
+	state.seenAt = seenAt
+	state.seen[fcnt] = seenAt
+	return res
+}
+
+// pruneFCntStates remove devices not seen within dedup window at most once per window,
+// they are loaded from device again on next uplink. fcntMu must be held
+func pruneFCntStates(now time.Time) {
+	if now.Sub(fcntPruned) < dedupWindow {
+		return
+	}
+	for eui, state := range fcntStates {
+		if now.Sub(state.seenAt) > dedupWindow {
+			delete(fcntStates, eui)
+		}
+	}
+	fcntPruned = now
+}
+
+// trackFCnt check frame counter and record statistics, returns classification of the frame counter
+// frames without frame counter are only counted as received, late frames filling a gap decrease lost
+func trackFCnt(dev storage.Device, data DataUpPayloadChan, seenAt time.Time) fcntResult {
+	var res fcntResult
+	if data.HasFCnt {
+		res = checkFCnt(dev, data.FCnt, seenAt)
+	}
+
+	var received, duplicates, resets int64 = 1, 0, 0
+	if res.duplicate {
+		received, duplicates = 0, 1
+	}
+	if res.reset {
+		resets = 1
+		log.WithField("device", data.DevEUI).WithField("fcnt", data.FCnt).Warn("frame counter reset")
+	}
+	if res.lost > 0 {
+		log.WithField("device", data.DevEUI).WithField("lost", res.lost).Warn("frame counter gap")
+	}
+
+	if err := storage.IncFCntStats(dev.DeviceEUI, received, res.lost, duplicates, resets); err != nil {
+		log.WithError(err).WithField("device", data.DevEUI).Error("update frame counter stats error")
+	}
+	return res
+}
@@ -0,0 +1,118 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/maxiiot/devicebridge/storage"
+)
+
+func TestCheckFCnt(t *testing.T) {
+	now := time.Now()
+	dev := storage.Device{DeviceEUI: storage.EUI64{0x01}}
+	steps := []struct {
+		fcnt uint32
+		at   time.Duration
+		want fcntResult
+	}{
+		{10, 0, fcntResult{}},
+		{10, time.Second, fcntResult{duplicate: true}},
+		{11, 2 * time.Second, fcntResult{}},
+		// late copy of 10 after 11
+		{10, 3 * time.Second, fcntResult{duplicate: true}},
+		{14, 4 * time.Second, fcntResult{lost: 2}},
+		// 12 arrives out of order, not seen before
+		{12, 5 * time.Second, fcntResult{late: true, lost: -1}},
+		{12, 6 * time.Second, fcntResult{duplicate: true}},
+		{15, 7 * time.Second, fcntResult{}},
+		// below every frame counter seen
+		{8, 8 * time.Second, fcntResult{reset: true}},
+		{8, 9 * time.Second, fcntResult{duplicate: true}},
+		// restart after long silence
+		{1, 2 * DefaultDedupWindow, fcntResult{reset: true}},
+		{2, 2*DefaultDedupWindow + time.Second, fcntResult{}},
+	}
+	for i, step := range steps {
+		seenAt := now.Add(step.at)
+		got := checkFCnt(dev, step.fcnt, seenAt)
+		if got != step.want {
+			t.Errorf("step %d: got %+v, want %+v", i, got, step.want)
+		}
+		// 与touchDevice一样记录最后的帧计数
+		if !got.duplicate && !got.late {
+			last := int64(step.fcnt)
+			dev.LastFCnt, dev.LastSeenAt = &last, &seenAt
+		}
+	}
+}
+
+func TestCheckFCntLargeReset(t *testing.T) {
+	now := time.Now()
+	last := int64(5000)
+	dev := storage.Device{DeviceEUI: storage.EUI64{0x02}, LastFCnt: &last, LastSeenAt: &now}
+
+	if got := checkFCnt(dev, 5000, now.Add(time.Second)); got != (fcntResult{duplicate: true}) {
+		t.Errorf("copy of stored fcnt: got %+v", got)
+	}
+	if got := checkFCnt(dev, 0, now.Add(2*time.Second)); got != (fcntResult{reset: true}) {
+		t.Errorf("large drop: got %+v, want reset", got)
+	}
+}
+
+func TestCheckFCntOutOfOrder(t *testing.T) {
+	now := time.Now()
+	dev := storage.Device{DeviceEUI: storage.EUI64{0x03}}
+
+	var lost int64
+	for i, fcnt := range []uint32{1, 3, 2} {
+		res := checkFCnt(dev, fcnt, now.Add(time.Duration(i)*time.Second))
+		if res.duplicate || res.reset {
+			t.Errorf("fcnt %d: unexpected %+v", fcnt, res)
+		}
+		lost += res.lost
+	}
+	if lost != 0 {
+		t.Errorf("1,3,2 should lose nothing, got lost=%d", lost)
+	}
+}
+
+func TestCheckFCntQuickRestart(t *testing.T) {
+	now := time.Now()
+	dev := storage.Device{DeviceEUI: storage.EUI64{0x04}}
+
+	var i int
+	check := func(fcnt uint32) fcntResult {
+		i++
+		return checkFCnt(dev, fcnt, now.Add(time.Duration(i)*time.Second))
+	}
+	for fcnt := uint32(1); fcnt <= 10; fcnt++ {
+		check(fcnt)
+	}
+	// 设备在窗口内重启,重新从1计数
+	if got := check(1); got != (fcntResult{reset: true}) {
+		t.Errorf("restart to 1: got %+v, want reset", got)
+	}
+	for fcnt := uint32(2); fcnt <= 10; fcnt++ {
+		if got := check(fcnt); got != (fcntResult{}) {
+			t.Errorf("fcnt %d after restart: got %+v", fcnt, got)
+		}
+	}
+	if got := check(10); got != (fcntResult{duplicate: true}) {
+		t.Errorf("copy after restart: got %+v, want duplicate", got)
+	}
+}
+
+func TestPruneFCntStates(t *testing.T) {
+	now := time.Now()
+	dev := storage.Device{DeviceEUI: storage.EUI64{0x05}}
+	checkFCnt(dev, 1, now)
+
+	fcntMu.Lock()
+	fcntPruned = time.Time{}
+	pruneFCntStates(now.Add(2 * DefaultDedupWindow))
+	_, ok := fcntStates[dev.DeviceEUI]
+	fcntMu.Unlock()
+	if ok {
+		t.Error("device not seen within dedup window should be pruned")
+	}
+}
@@ -1,8 +1,8 @@
 package backend
 
 import (
-	"errors"
-	"fmt"
+	"encoding/json"
+	"time"
 
 	paho "github.com/eclipse/paho.mqtt.golang"
 	"github.com/maxiiot/devicebridge/backend/protocol"
@@ -10,73 +10,82 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-var topic = "device/%s/%s"
-
 // HandleUplink handle uplink data
-func HandleUplink(conn paho.Client, data DataUpPayloadChan) error {
+func HandleUplink(conn paho.Client, data DataUpPayloadChan) (err error) {
+	uplinkLog := newUplinkLog(data)
+	defer func() {
+		if err != nil {
+			if uplinkLog.DecodeStatus == storage.DecodeStatusSuccess {
+				uplinkLog.DecodeStatus = storage.DecodeStatusFailed
+			}
+			uplinkLog.DecodeError = err.Error()
+		}
+		if e := storage.CreateUplinkLog(uplinkLog); e != nil {
+			log.WithError(e).WithField("device", data.DevEUI).Error("store uplink log error")
+		}
+	}()
+
 	dev, err := storage.GetDeviceByEUI(data.DevEUI.String())
 	if err != nil {
+		uplinkLog.DecodeStatus = storage.DecodeStatusUnknownDevice
 		return err
 	}
+	uplinkLog.ProtocolType = dev.ProtocolType
 
-	switch dev.ProtocolType {
-	case storage.ProtocolHumiture:
-		var hums protocol.Humitures
-		err := hums.Unmarshal(data.Data)
-		if err != nil {
-			return err
-		}
-		for _, hum := range hums.Hums {
+	fcnt := trackFCnt(dev, data, uplinkLog.ReceivedAt)
+	if fcnt.duplicate {
+		uplinkLog.DecodeStatus = storage.DecodeStatusDuplicate
+		return nil
+	}
+	touchDevice(conn, dev, data, fcnt.late, uplinkLog.ReceivedAt)
 
-			topictemp := fmt.Sprintf(topic, data.DevEUI, "temp")
-			if token := conn.Publish(topictemp, 0, false, fmt.Sprintf("%.1f", hum.Temperature)); token.Wait() && token.Error() != nil {
-				log.WithError(token.Error()).Errorf("publish %s %.1f", topictemp, hum.Temperature)
-			} else {
-				log.Infof("publish success,topic:%s msg:%.1f", topictemp, hum.Temperature)
-			}
+	decoder, ok := protocol.Lookup(dev.ProtocolType)
+	if !ok {
+		uplinkLog.DecodeStatus = storage.DecodeStatusNoDecoder
+		return nil
+	}
 
-			topichum := fmt.Sprintf(topic, data.DevEUI, "hum")
-			if token := conn.Publish(topichum, 0, false, fmt.Sprintf("%.1f", hum.Humidity)); token.Wait() && token.Error() != nil {
-				log.WithError(token.Error()).Errorf("publish %s %.1f", topichum, hum.Humidity)
-			} else {
-				log.Infof("publish success,topic:%s msg:%.1f", topichum, hum.Humidity)
+	decoded, err := decoder.Decode(data.Data)
+	if err != nil {
+		if reason := protocol.RejectReason(err); reason != "" {
+			if e := storage.IncRejectedFrame(dev.DeviceEUI, dev.ProtocolType, reason, err.Error()); e != nil {
+				log.WithError(e).WithField("device", data.DevEUI).Error("count rejected frame error")
 			}
+		}
+		return err
+	}
 
-			topicele := fmt.Sprintf(topic, data.DevEUI, "ele")
-			if token := conn.Publish(topicele, 0, false, fmt.Sprintf("%.1f", hum.Electricity)); token.Wait() && token.Error() != nil {
-				log.WithError(token.Error()).Errorf("publish %s %.1f", topicele, hum.Electricity)
-			} else {
-				log.Infof("publish success,topic: %s msg: %.1f", topicele, hum.Electricity)
-			}
+	ms := toStorageMeasurements(data.DevEUI, decoded.Measurements, uplinkLog.ReceivedAt)
+	if err := storage.CreateMeasurements(ms); err != nil {
+		log.WithError(err).WithField("device", data.DevEUI).Error("store measurements error")
+	}
 
-		}
-	case storage.ProtocolSmoke:
-		if len(data.Data) < 5 {
-			return errors.New("data format error.")
-		}
-		var smoke protocol.Smoke
-		err := smoke.Unmarshal(data.Data[4:])
-		if err != nil {
-			return err
-		}
-		if smoke.IsHeartBeat {
-			topicsmoke := fmt.Sprintf(topic, data.DevEUI, "smoke")
-			if token := conn.Publish(topicsmoke, 0, false, "heartbeat"); token.Wait() && token.Error() != nil {
-				log.WithError(token.Error()).Errorf("publish %s %s", topicsmoke, "heartbeat")
-			} else {
-				log.Infof("publish success,topic: %s msg: %s", topicsmoke, "heartbeat")
-			}
-		}
-		if smoke.Alarm != nil {
-			topicsmoke := fmt.Sprintf(topic, data.DevEUI, "smoke")
-			if token := conn.Publish(topicsmoke, 0, false, smoke.Alarm.String()); token.Wait() && token.Error() != nil {
-				log.WithError(token.Error()).Errorf("publish %s %s", topicsmoke, smoke.Alarm.String())
-			} else {
-				log.Infof("publish success,topic: %s msg: %s", topicsmoke, smoke.Alarm.String())
-			}
-		}
+	handleAlarms(dev, decoded, uplinkLog.ReceivedAt)
+
+	publishDecoded(conn, dev, data, decoded)
 
+	if a, ok := decoded.Object.(protocol.Acknowledger); ok {
+		handleAcks(conn, dev, data, a.Acknowledgements())
 	}
 
 	return nil
 }
+
+// newUplinkLog returns uplink log of received frame
+func newUplinkLog(data DataUpPayloadChan) storage.UplinkLog {
+	rxMetadata, err := json.Marshal(data.RxMetadata)
+	if err != nil {
+		log.WithError(err).WithField("device", data.DevEUI).Error("marshal rx metadata error")
+	}
+
+	return storage.UplinkLog{
+		DeviceEUI:     data.DevEUI,
+		ApplicationID: data.ApplicationID,
+		Data:          data.Data,
+		FCnt:          data.FCnt,
+		FPort:         data.FPort,
+		RxMetadata:    rxMetadata,
+		DecodeStatus:  storage.DecodeStatusSuccess,
+		ReceivedAt:    time.Now(),
+	}
+}
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/maxiiot/devicebridge/backend"
+	"github.com/maxiiot/devicebridge/utils"
+)
+
+// DownlinkClient send downlink by lora-app-server api
+type DownlinkClient struct {
+	client *utils.LoraHTTPClient
+}
+
+// NewDownlinkClient returns lora-app-server downlink client
+func NewDownlinkClient(server, user, password string) *DownlinkClient {
+	return &DownlinkClient{
+		client: utils.NewLoraHTTPClient(server, user, password),
+	}
+}
+
+// SendDownlink enqueue downlink to device queue
+func (c *DownlinkClient) SendDownlink(p backend.DownlinkPayload) error {
+	_, err := c.client.Post(fmt.Sprintf("/api/devices/%s/queue", p.DevEUI), nil, nil, map[string]interface{}{
+		"deviceQueueItem": map[string]interface{}{
+			"devEUI":    p.DevEUI.String(),
+			"confirmed": p.Confirmed,
+			"fPort":     p.FPort,
+			"data":      base64.StdEncoding.EncodeToString(p.Data),
+		},
+	})
+	return err
+}
+
+// IsConnected lora-app-server api is stateless, always true
+func (c *DownlinkClient) IsConnected() bool {
+	return true
+}
@@ -74,11 +74,7 @@ func loraAppServer(dataChan chan backend.DataUpPayloadChan) gin.HandlerFunc {
 			return
 		}
 
-		if b, err := hex.DecodeString(rxdata.Data); err == nil {
-			data := backend.DataUpPayloadChan{
-				Data:   b,
-				DevEUI: rxdata.DevEUI,
-			}
+		if data, err := backend.NewDataUpPayloadChan(rxdata); err == nil {
 			dataChan <- data
 		} else {
 			log.WithError(err).Error("hex deocde payload data error ")
@@ -107,8 +103,12 @@ func (b *HttpBackend) HandleUplinks(conn paho.Client, wg *sync.WaitGroup) {
 			defer wg.Done()
 			if err := backend.HandleUplink(conn, uplink); err != nil {
 				log.WithFields(log.Fields{
-					"device": uplink.DevEUI,
-					"data":   hex.EncodeToString(uplink.Data),
+					"device":  uplink.DevEUI,
+					"data":    hex.EncodeToString(uplink.Data),
+					"fcnt":    uplink.FCnt,
+					"gateway": uplink.RxMetadata.GatewayEUI,
+					"rssi":    uplink.RxMetadata.RSSI,
+					"snr":     uplink.RxMetadata.LoRaSNR,
 				}).Errorf("process device uplink data error: %s", err)
 			}
 		}(uplink)
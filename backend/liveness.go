@@ -0,0 +1,38 @@
+package backend
+
+import (
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/maxiiot/devicebridge/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+// touchDevice record device liveness of uplink, publish online when device was offline or unknown,
+// frame counter of late frame is not recorded to keep last_fcnt from going backwards
+func touchDevice(conn paho.Client, dev storage.Device, data DataUpPayloadChan, late bool, seenAt time.Time) {
+	var fcnt *uint32
+	if data.HasFCnt && !late {
+		fcnt = &data.FCnt
+	}
+	prev, err := storage.TouchDevice(dev.DeviceEUI, seenAt, fcnt, data.RxMetadata.RSSI, data.RxMetadata.LoRaSNR)
+	if err != nil {
+		log.WithError(err).WithField("device", data.DevEUI).Error("update device liveness error")
+		return
+	}
+	if prev != storage.DeviceStatusOnline {
+		log.WithField("device", data.DevEUI).Info("device online")
+		publish(conn, publishTopic(dev, data, "status"), retainFields["status"], storage.DeviceStatusOnline)
+	}
+}
+
+// PublishDeviceStatus publish device online/offline transition to status topic
+func PublishDeviceStatus(conn paho.Client, dev storage.Device, status string) {
+	data := DataUpPayloadChan{DevEUI: dev.DeviceEUI, ApplicationID: dev.ApplicationID}
+	if data.ApplicationID == 0 {
+		if appID, err := storage.GetLastApplicationID(dev.DeviceEUI); err == nil {
+			data.ApplicationID = appID
+		}
+	}
+	publish(conn, publishTopic(dev, data, "status"), retainFields["status"], status)
+}
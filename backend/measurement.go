@@ -0,0 +1,67 @@
+package backend
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/maxiiot/devicebridge/backend/protocol"
+	"github.com/maxiiot/devicebridge/storage"
+)
+
+// toStorageMeasurements convert decoded measurements to storage model,
+// samples without time are stored at receive time
+func toStorageMeasurements(devEUI storage.EUI64, ms []protocol.Measurement, receivedAt time.Time) []storage.Measurement {
+	list := make([]storage.Measurement, 0, len(ms))
+	for _, m := range ms {
+		sm := storage.Measurement{
+			DeviceEUI:  devEUI,
+			Field:      m.Field,
+			SampleTime: m.DateTime,
+			ReceivedAt: receivedAt,
+		}
+		if sm.SampleTime.IsZero() {
+			sm.SampleTime = receivedAt
+		}
+		sm.Value, sm.TextValue = measurementValue(m.Value)
+		list = append(list, sm)
+	}
+	return list
+}
+
+// measurementValue returns numeric value or text value
+func measurementValue(v interface{}) (*float64, *string) {
+	var f float64
+	switch n := v.(type) {
+	case float64:
+		f = n
+	case float32:
+		f = float64(n)
+	case int:
+		f = float64(n)
+	case int8:
+		f = float64(n)
+	case int16:
+		f = float64(n)
+	case int32:
+		f = float64(n)
+	case int64:
+		f = float64(n)
+	case uint8:
+		f = float64(n)
+	case uint16:
+		f = float64(n)
+	case uint32:
+		f = float64(n)
+	case uint64:
+		f = float64(n)
+	case string:
+		return nil, &n
+	case fmt.Stringer:
+		s := n.String()
+		return nil, &s
+	default:
+		s := fmt.Sprintf("%v", n)
+		return nil, &s
+	}
+	return &f, nil
+}
@@ -1,6 +1,7 @@
 package backend
 
 import (
+	"encoding/hex"
 	"time"
 
 	"github.com/maxiiot/devicebridge/storage"
@@ -50,15 +51,84 @@ type DataUpPayload struct {
 	RXInfo     []RXInfo      `json:"rxInfo"`
 	TXInfo     TXInfo        `json:"txInfo"`
 	FPort      uint8         `json:"port"`
-	FCnt       uint32        `json:"uplink_count"`
+	FCnt       *uint32       `json:"uplink_count"` // 空表示上行数据不带帧计数
 	//GatewayList     []string        `json:"gateway_list,omitempty"`
 	Object interface{} `json:"object,omitempty"`
 }
 
+// RxMetadata radio metadata of uplink
+type RxMetadata struct {
+	Time       *time.Time    `json:"time,omitempty"`
+	GatewayEUI storage.EUI64 `json:"gateway_eui"`
+	RSSI       int32         `json:"rssi"`
+	LoRaSNR    float64       `json:"lsnr"`
+	Frequency  float64       `json:"frequency"`
+	DataRate   string        `json:"data_rate"`
+	ADR        bool          `json:"adr"`
+	RXInfo     []RXInfo      `json:"rx_info"`
+	TXInfo     TXInfo        `json:"tx_info"`
+}
+
 // DataUpPayloadChan DataUpPayloadChan
 type DataUpPayloadChan struct {
-	Data   []byte
-	DevEUI storage.EUI64
+	Data          []byte
+	DevEUI        storage.EUI64
+	ApplicationID int64
+	FPort         uint8
+	FCnt          uint32
+	HasFCnt       bool // 上行数据带帧计数,否则不做去重和丢包统计
+	RxMetadata    RxMetadata
+}
+
+// NewDataUpPayloadChan decode hex payload data and keep radio metadata
+func NewDataUpPayloadChan(p DataUpPayload) (DataUpPayloadChan, error) {
+	data, err := hex.DecodeString(p.Data)
+	if err != nil {
+		return DataUpPayloadChan{}, err
+	}
+
+	meta := RxMetadata{
+		Time:       p.Time,
+		GatewayEUI: p.GatewayEUI,
+		RSSI:       p.RSSI,
+		LoRaSNR:    p.LoRaSNR,
+		Frequency:  p.Frequency,
+		DataRate:   p.DataRate,
+		ADR:        p.ADR,
+		RXInfo:     p.RXInfo,
+		TXInfo:     p.TXInfo,
+	}
+	// 只有rxInfo时取信号最好的网关
+	if meta.GatewayEUI == (storage.EUI64{}) && len(p.RXInfo) > 0 {
+		best := p.RXInfo[0]
+		for _, rx := range p.RXInfo[1:] {
+			if rx.RSSI > best.RSSI {
+				best = rx
+			}
+		}
+		meta.GatewayEUI = best.GatewayID
+		meta.RSSI = int32(best.RSSI)
+		meta.LoRaSNR = best.LoRaSNR
+		if meta.Time == nil {
+			meta.Time = best.Time
+		}
+	}
+	if meta.Frequency == 0 && p.TXInfo.Frequency > 0 {
+		meta.Frequency = float64(p.TXInfo.Frequency) / 1000000.
+	}
+
+	dataChan := DataUpPayloadChan{
+		Data:          data,
+		DevEUI:        p.DevEUI,
+		ApplicationID: p.ApplicationID,
+		FPort:         p.FPort,
+		RxMetadata:    meta,
+	}
+	if p.FCnt != nil {
+		dataChan.FCnt = *p.FCnt
+		dataChan.HasFCnt = true
+	}
+	return dataChan, nil
 }
 
 // type ACKNotification struct {
@@ -14,6 +14,7 @@ import (
 	"crypto/x509"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"sync"
@@ -27,16 +28,19 @@ import (
 
 // Config mqtt broker configuration
 type Config struct {
-	Server              string `mapstructure:"server" json:"server"`
-	Username            string `mapstructure:"username" json:"username"`
-	Password            string `mapstructure:"password" json:"password"`
-	QOS                 uint8  `mapstructure:"qos" json:"qos"`
-	CleanSession        bool   `mapstructure:"clean_session" json:"clean_session"`
-	ClientID            string `mapstructure:"client_id" json:"client_id"`
-	CACert              string `mapstructure:"ca_cert" json:"ca_cert"`
-	TLSCert             string `mapstructure:"tls_cert" json:"tls_cert"`
-	TLSKey              string `mapstructure:"tls_key" json:"tls_key"`
-	UplinkTopicTemplate string `mapstructure:"uplink_topic_template" json:"uplink_topic_template"`
+	Server                string   `mapstructure:"server" json:"server"`
+	Username              string   `mapstructure:"username" json:"username"`
+	Password              string   `mapstructure:"password" json:"password"`
+	QOS                   uint8    `mapstructure:"qos" json:"qos"`
+	CleanSession          bool     `mapstructure:"clean_session" json:"clean_session"`
+	ClientID              string   `mapstructure:"client_id" json:"client_id"`
+	CACert                string   `mapstructure:"ca_cert" json:"ca_cert"`
+	TLSCert               string   `mapstructure:"tls_cert" json:"tls_cert"`
+	TLSKey                string   `mapstructure:"tls_key" json:"tls_key"`
+	UplinkTopicTemplate   string   `mapstructure:"uplink_topic_template" json:"uplink_topic_template"`
+	DownlinkTopicTemplate string   `mapstructure:"downlink_topic_template" json:"downlink_topic_template"`
+	TopicTemplate         string   `mapstructure:"topic_template" json:"topic_template"`
+	RetainFields          []string `mapstructure:"retain_fields" json:"retain_fields"`
 	// AckTopicTemplate    string `mapstructure:"ack_topic_template" json:"ack_topic_template"`
 }
 
@@ -155,11 +159,7 @@ func (b *Backend) rxPacketHandler(c paho.Client, msg paho.Message) {
 		"payload": rxdata.Data,
 		"device":  rxdata.DevEUI,
 	}).Info("mqtt: uplink frame received")
-	if data, err := hex.DecodeString(rxdata.Data); err == nil {
-		dataChan := backend.DataUpPayloadChan{
-			Data:   data,
-			DevEUI: rxdata.DevEUI,
-		}
+	if dataChan, err := backend.NewDataUpPayloadChan(rxdata); err == nil {
 		b.rxPacketChan <- dataChan
 	} else {
 		log.WithError(err).Error("hex deocde payload data error ")
@@ -189,6 +189,38 @@ func (b *Backend) Close() error {
 	return nil
 }
 
+// SendDownlink publish downlink to application/<app>/device/<eui>/tx
+func (b *Backend) SendDownlink(p backend.DownlinkPayload) error {
+	if b.config.DownlinkTopicTemplate == "" {
+		return errors.New("backend/mqtt: downlink_topic_template is not configured")
+	}
+
+	payload, err := json.Marshal(backend.DownlinkTxPayload{
+		DevEUI:    p.DevEUI,
+		Confirmed: p.Confirmed,
+		FPort:     p.FPort,
+		Data:      p.Data,
+	})
+	if err != nil {
+		return err
+	}
+
+	topic := fmt.Sprintf(b.config.DownlinkTopicTemplate, p.ApplicationID, p.DevEUI)
+	log.WithFields(log.Fields{
+		"topic": topic,
+		"qos":   b.config.QOS,
+	}).Info("backend/mqtt: publishing downlink")
+	if token := b.conn.Publish(topic, b.config.QOS, false, payload); token.Wait() && token.Error() != nil {
+		return token.Error()
+	}
+	return nil
+}
+
+// IsConnected returns whether connected to mqtt broker
+func (b *Backend) IsConnected() bool {
+	return b.conn.IsConnected()
+}
+
 // Notice notice
 func (b *Backend) Notice(notice map[string]bool) {
 	b.subNotice <- notice
@@ -271,8 +303,12 @@ func (b *Backend) HandleUplinks(conn paho.Client, wg *sync.WaitGroup) {
 			defer wg.Done()
 			if err := backend.HandleUplink(conn, uplink); err != nil {
 				log.WithFields(log.Fields{
-					"device": uplink.DevEUI,
-					"data":   hex.EncodeToString(uplink.Data),
+					"device":  uplink.DevEUI,
+					"data":    hex.EncodeToString(uplink.Data),
+					"fcnt":    uplink.FCnt,
+					"gateway": uplink.RxMetadata.GatewayEUI,
+					"rssi":    uplink.RxMetadata.RSSI,
+					"snr":     uplink.RxMetadata.LoRaSNR,
 				}).Errorf("process device uplink data error: %s", err)
 			}
 		}(uplink)
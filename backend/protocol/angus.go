@@ -42,11 +42,14 @@ func (a *Angus) Unmarshal(data []byte) (err error) {
 
 	length := len(data)
 	if length <= 21 {
-		return fmt.Errorf("Angus payload length must >=21")
+		return frameErrorf("payload", ErrBadLength, "Angus payload length must >=21")
 	}
 
 	if data[0] != 0xAA {
-		return fmt.Errorf("Angus payload start with 0xAA")
+		return frameErrorf("frame_header", ErrBadHeader, "Angus payload start with 0xAA")
+	}
+	if crc := checksum(data[1 : length-1]); crc != data[length-1] {
+		return frameErrorf("crc", ErrBadCRC, "got 0x%02x, want 0x%02x", data[length-1], crc)
 	}
 	st := 0
 	a.FrameHeader = data[st]
@@ -80,35 +83,74 @@ func (a *Angus) Unmarshal(data []byte) (err error) {
 	switch a.Code {
 	case 0x01:
 		if length != 22 {
-			return fmt.Errorf("报警功能数据长度应为22")
+			return frameErrorf("payload", ErrBadLength, "报警功能数据长度应为22")
 		}
 		alert := &AngusAlert{}
-		alert.Unmarshal(data[st : st+1])
+		if err := alert.Unmarshal(data[st : st+1]); err != nil {
+			return &FieldError{Field: "data_field", Err: err}
+		}
 		a.DataField = alert
 	case 0x02:
 		if length != 28 {
-			return fmt.Errorf("传感器信息数据长度应为28")
+			return frameErrorf("payload", ErrBadLength, "传感器信息数据长度应为28")
 		}
 		sensor := &AngusSensor{}
-		sensor.Unmarshal(data[st : st+7])
+		if err := sensor.Unmarshal(data[st : st+7]); err != nil {
+			return &FieldError{Field: "data_field", Err: err}
+		}
 		a.DataField = sensor
 	case 0x03:
 		if length != 27 {
-			return fmt.Errorf("心跳包信息数据长度应为27")
+			return frameErrorf("payload", ErrBadLength, "心跳包信息数据长度应为27")
 		}
 		hb := &AngusHeartbeat{}
-		hb.Unmarshal(data[st : st+6])
+		if err := hb.Unmarshal(data[st : st+6]); err != nil {
+			return &FieldError{Field: "data_field", Err: err}
+		}
 		a.DataField = hb
 	}
 	a.CRC = data[length-1]
 	return nil
 }
 
+// Marshal 数据编码, 经纬度取原始值, 功能码和数据长度由数据域决定, 校验码为除帧头外的字节和
+func (a Angus) Marshal() ([]byte, error) {
+	var code uint8
+	switch a.DataField.(type) {
+	case *AngusAlert:
+		code = 0x01
+	case *AngusSensor:
+		code = 0x02
+	case *AngusHeartbeat:
+		code = 0x03
+	default:
+		return nil, fieldErrorf("data_field", "unsupported angus data field")
+	}
+	field, err := a.DataField.Marshal()
+	if err != nil {
+		return nil, &FieldError{Field: "data_field", Err: err}
+	}
+
+	b := make([]byte, 20, 21+len(field))
+	b[0] = 0xAA
+	binary.BigEndian.PutUint32(b[1:5], uint32(a.UTC.Unix()))
+	binary.BigEndian.PutUint32(b[5:9], a.OriginLatitude)
+	binary.BigEndian.PutUint32(b[9:13], a.OriginLongitude)
+	b[13] = a.Speed
+	binary.BigEndian.PutUint16(b[14:16], a.Azimuth)
+	binary.BigEndian.PutUint16(b[16:18], a.Altitude)
+	b[18] = code
+	b[19] = uint8(len(field))
+	b = append(b, field...)
+	return append(b, checksum(b[1:])), nil
+}
+
 // AngusAlert 报警提醒
 type AngusAlert struct {
 	SOS        bool `json:"sos"`         // SOS 警报
 	LowBattery bool `json:"low_battery"` // 低电压警报
 	Remove     bool `json:"remove"`      // 摘除警报
+	Code       byte `json:"code"`        // 原始报警码
 }
 
 // Unmarshal  AngusAlert unmarshal
@@ -116,6 +158,7 @@ func (alert *AngusAlert) Unmarshal(data []byte) error {
 	if len(data) != 1 {
 		return fmt.Errorf("报警提醒长度为一个字节")
 	}
+	alert.Code = data[0]
 	if data[0] == 0x01 {
 		alert.SOS = true
 	}
@@ -128,6 +171,36 @@ func (alert *AngusAlert) Unmarshal(data []byte) error {
 	return nil
 }
 
+// Marshal AngusAlert marshal
+func (alert *AngusAlert) Marshal() ([]byte, error) {
+	switch {
+	case alert.SOS:
+		return []byte{0x01}, nil
+	case alert.LowBattery:
+		return []byte{0x02}, nil
+	case alert.Remove:
+		return []byte{0x04}, nil
+	default:
+		return []byte{alert.Code}, nil
+	}
+}
+
+// String returns alert name, unknown codes are named by code, e.g. unknown_0x08
+func (alert AngusAlert) String() string {
+	switch {
+	case alert.SOS:
+		return "sos"
+	case alert.LowBattery:
+		return "low_battery"
+	case alert.Remove:
+		return "remove"
+	case alert.Code == 0x00:
+		return "normal"
+	default:
+		return fmt.Sprintf("unknown_0x%02x", alert.Code)
+	}
+}
+
 // AngusSensor 传感器信息
 type AngusSensor struct {
 	StepNumber uint16 `json:"step_number"` // 步数
@@ -147,6 +220,15 @@ func (as *AngusSensor) Unmarshal(data []byte) error {
 	return nil
 }
 
+// Marshal AngusSensor marshal
+func (as *AngusSensor) Marshal() ([]byte, error) {
+	b := make([]byte, 7)
+	binary.BigEndian.PutUint16(b[:2], as.StepNumber)
+	binary.BigEndian.PutUint32(b[2:6], as.BusinessID)
+	b[6] = as.Power
+	return b, nil
+}
+
 // AngusHeartbeat 心跳包信息
 type AngusHeartbeat struct {
 	StepNumber uint16 `json:"step_number"` // 步数
@@ -163,3 +245,72 @@ func (ah *AngusHeartbeat) Unmarshal(data []byte) error {
 
 	return nil
 }
+
+// Marshal AngusHeartbeat marshal
+func (ah *AngusHeartbeat) Marshal() ([]byte, error) {
+	b := make([]byte, 6)
+	binary.BigEndian.PutUint16(b[:2], ah.StepNumber)
+	binary.BigEndian.PutUint32(b[2:6], ah.BusinessID)
+	return b, nil
+}
+
+// Alarms returns angus alert, no alarm for normal code
+func (a Angus) Alarms() []Alarm {
+	if alert, ok := a.DataField.(*AngusAlert); ok && alert.String() != "normal" {
+		return []Alarm{{Name: alert.String(), DateTime: a.UTC}}
+	}
+	return nil
+}
+
+// Normal returns whether the frame is sensor info or heartbeat
+func (a Angus) Normal() bool {
+	switch a.DataField.(type) {
+	case *AngusSensor, *AngusHeartbeat:
+		return true
+	default:
+		return false
+	}
+}
+
+// Measurements returns angus position, alarm, step and power measurements
+func (a Angus) Measurements() []Measurement {
+	ms := []Measurement{
+		{Field: "latitude", Value: a.Latitude, DateTime: a.UTC, Format: "%.6f"},
+		{Field: "longitude", Value: a.Longitude, DateTime: a.UTC, Format: "%.6f"},
+		{Field: "speed", Value: a.Speed, DateTime: a.UTC},
+		{Field: "azimuth", Value: a.Azimuth, DateTime: a.UTC},
+		{Field: "altitude", Value: a.Altitude, DateTime: a.UTC},
+	}
+	switch field := a.DataField.(type) {
+	case *AngusAlert:
+		ms = append(ms, Measurement{Field: "alarm", Value: field.String(), DateTime: a.UTC})
+	case *AngusSensor:
+		ms = append(ms,
+			Measurement{Field: "step", Value: field.StepNumber, DateTime: a.UTC},
+			Measurement{Field: "power", Value: field.Power, DateTime: a.UTC},
+		)
+	case *AngusHeartbeat:
+		ms = append(ms,
+			Measurement{Field: "heartbeat", Value: "heartbeat", DateTime: a.UTC},
+			Measurement{Field: "step", Value: field.StepNumber, DateTime: a.UTC},
+		)
+	}
+	return ms
+}
+
+func decodeAngus(data []byte) (Decoded, error) {
+	var a Angus
+	if err := a.Unmarshal(data); err != nil {
+		return Decoded{}, err
+	}
+	return Decoded{Object: a, Measurements: a.Measurements()}, nil
+}
+
+func init() {
+	Register(Decoder{
+		Name:        "angus",
+		Description: "Maxiiot angus livestock tracker",
+		Fields:      []string{"latitude", "longitude", "speed", "azimuth", "altitude", "alarm", "heartbeat", "step", "power"},
+		Decode:      decodeAngus,
+	})
+}
@@ -3,7 +3,9 @@ package protocol
 import (
 	"encoding/hex"
 	"encoding/json"
+	"math"
 	"testing"
+	"time"
 )
 
 func TestAngusUnamrshal(t *testing.T) {
@@ -12,7 +14,121 @@ func TestAngusUnamrshal(t *testing.T) {
 		t.Error("decode string data error:", err)
 	}
 	ang := Angus{}
-	ang.Unmarshal(data)
+	if err := ang.Unmarshal(data); err != nil {
+		t.Fatal("angus unmarshal error:", err)
+	}
+	if ang.UTC.Unix() != 1554780540 || ang.OriginLatitude != 22586414 || ang.OriginLongitude != 113913436 {
+		t.Errorf("unexpected time or position: %+v", ang)
+	}
+	// 经纬度由wgs84转换为gcj02
+	if math.Abs(ang.Latitude-22.583368) > 1e-6 || math.Abs(ang.Longitude-113.918299) > 1e-6 {
+		t.Errorf("unexpected gcj02 position: %f,%f", ang.Latitude, ang.Longitude)
+	}
+	if ang.Speed != 1 || ang.Azimuth != 347 || ang.Altitude != 65514 || ang.CRC != 0x66 {
+		t.Errorf("unexpected frame fields: %+v", ang)
+	}
+	alert, ok := ang.DataField.(*AngusAlert)
+	if !ok || !alert.Remove || alert.SOS || alert.LowBattery {
+		t.Errorf("unexpected data field: %#v", ang.DataField)
+	}
 	b, _ := json.MarshalIndent(ang, "", " ")
 	t.Log(string(b))
 }
+
+func TestAngusDecode(t *testing.T) {
+	data, err := hex.DecodeString("aa5cac117c0158a42e06ca2e5c01015bffea01010466")
+	if err != nil {
+		t.Error("decode string data error:", err)
+	}
+	dec, ok := Lookup("angus")
+	if !ok {
+		t.Fatal("angus decoder not registered")
+	}
+	decoded, err := dec.Decode(data)
+	if err != nil {
+		t.Fatal("angus decode error:", err)
+	}
+	checkMeasurements(t, decoded.Measurements, [][2]string{
+		{"latitude", "22.583368"},
+		{"longitude", "113.918299"},
+		{"speed", "1"},
+		{"azimuth", "347"},
+		{"altitude", "65514"},
+		{"alarm", "remove"},
+	})
+	alarms := decoded.Object.(Angus).Alarms()
+	if len(alarms) != 1 || alarms[0].Name != "remove" || !alarms[0].DateTime.Equal(time.Unix(1554780540, 0)) {
+		t.Errorf("unexpected alarms: %v", alarms)
+	}
+
+	// 传感器信息发布步数和电量
+	sensor := Angus{
+		UTC:             time.Unix(1554780540, 0),
+		OriginLatitude:  22586414,
+		OriginLongitude: 113913436,
+		DataField:       &AngusSensor{StepNumber: 1234, BusinessID: 7, Power: 85},
+	}
+	b, err := sensor.Marshal()
+	if err != nil {
+		t.Fatal("angus marshal error:", err)
+	}
+	decoded, err = dec.Decode(b)
+	if err != nil {
+		t.Fatal("angus decode error:", err)
+	}
+	checkMeasurements(t, decoded.Measurements, [][2]string{
+		{"latitude", "22.583368"},
+		{"longitude", "113.918299"},
+		{"speed", "0"},
+		{"azimuth", "0"},
+		{"altitude", "0"},
+		{"step", "1234"},
+		{"power", "85"},
+	})
+	if a := decoded.Object.(Angus); len(a.Alarms()) != 0 || !a.Normal() {
+		t.Errorf("sensor info should be normal: %v", a.Alarms())
+	}
+}
+
+// checkMeasurements compare measurement field names and values in order
+func checkMeasurements(t *testing.T, ms []Measurement, want [][2]string) {
+	t.Helper()
+	if len(ms) != len(want) {
+		t.Fatalf("unexpected measurements count %d: %v", len(ms), ms)
+	}
+	for i, m := range ms {
+		if m.Field != want[i][0] || m.String() != want[i][1] {
+			t.Errorf("measurement %d: got %s=%s, want %s=%s", i, m.Field, m.String(), want[i][0], want[i][1])
+		}
+	}
+}
+
+func TestAngusAlarms(t *testing.T) {
+	for _, c := range []struct {
+		code byte
+		want string
+	}{
+		{0x01, "sos"},
+		{0x02, "low_battery"},
+		{0x04, "remove"},
+		{0x08, "unknown_0x08"},
+		{0x03, "unknown_0x03"},
+	} {
+		alert := &AngusAlert{}
+		if err := alert.Unmarshal([]byte{c.code}); err != nil {
+			t.Fatal("alert unmarshal error:", err)
+		}
+		a := Angus{DataField: alert}
+		if alarms := a.Alarms(); len(alarms) != 1 || alarms[0].Name != c.want {
+			t.Errorf("code %#02x: unexpected alarms: %v", c.code, alarms)
+		}
+		if b, _ := alert.Marshal(); b[0] != c.code {
+			t.Errorf("code %#02x: unexpected marshal: %x", c.code, b)
+		}
+	}
+
+	a := Angus{DataField: &AngusAlert{}}
+	if alarms := a.Alarms(); len(alarms) != 0 {
+		t.Errorf("normal alert should not open alarm: %v", alarms)
+	}
+}
@@ -0,0 +1,91 @@
+package protocol
+
+import (
+	"encoding/hex"
+	"fmt"
+)
+
+const (
+	maxiiotHeader byte = 0x18 // 帧起始标志
+	maxiiotEnd    byte = 0x81 // 帧结束标志
+)
+
+// CommandFunction function code and data of command
+type CommandFunction struct {
+	Code uint8  `json:"code"`
+	Data string `json:"data" example:"hex data"`
+}
+
+// Command typed downlink command of maxiiot devices
+type Command struct {
+	DeviceID  string            `json:"device_id,omitempty" example:"hex device id, optional for known protocols"`
+	Functions []CommandFunction `json:"functions"`
+}
+
+// EncodeCommand encode typed command to maxiiot downlink frame of the protocol
+func EncodeCommand(protocolName string, cmd Command) ([]byte, error) {
+	deviceID, ok := maxiiotDeviceID(protocolName)
+	if cmd.DeviceID != "" {
+		b, err := hex.DecodeString(cmd.DeviceID)
+		if err != nil || len(b) != 2 {
+			return nil, fieldErrorf("device_id", "device id must be 2 bytes hex")
+		}
+		copy(deviceID[:], b)
+	} else if !ok {
+		return nil, fmt.Errorf("protocol %s does not support typed command", protocolName)
+	}
+
+	if len(cmd.Functions) == 0 || len(cmd.Functions) > 0xff {
+		return nil, fieldErrorf("functions", "functions count must be 1-255")
+	}
+	body := []byte{byte(len(cmd.Functions))}
+	for _, f := range cmd.Functions {
+		data, err := hex.DecodeString(f.Data)
+		if err != nil {
+			return nil, &FieldError{Field: "data", Err: err}
+		}
+		if len(data) > 0xff {
+			return nil, fieldErrorf("data", "function data length must <=255")
+		}
+		body = append(body, f.Code, byte(len(data)))
+		body = append(body, data...)
+	}
+
+	return encodeMaxiiotFrame(TransCode{Direction: "Server2Dev"}, deviceID, body), nil
+}
+
+// FunctionCodes returns function codes of maxiiot downlink frame
+func FunctionCodes(frame []byte) ([]uint8, error) {
+	if len(frame) < 7 || frame[0] != maxiiotHeader || frame[len(frame)-1] != maxiiotEnd {
+		return nil, fieldErrorf("payload", "not a maxiiot frame")
+	}
+	body := frame[4 : len(frame)-2]
+	var codes []uint8
+	start := 1
+	for i := 0; i < int(body[0]); i++ {
+		if start+1 >= len(body) {
+			return nil, fieldErrorf("payload", "function data length error")
+		}
+		codes = append(codes, body[start])
+		start += 2 + int(body[start+1])
+	}
+	return codes, nil
+}
+
+// encodeMaxiiotFrame encode header, transcode, device id, body, crc and end
+func encodeMaxiiotFrame(tc TransCode, deviceID [2]byte, body []byte) []byte {
+	frame := make([]byte, 0, len(body)+6)
+	frame = append(frame, maxiiotHeader, tc.byte(), deviceID[0], deviceID[1])
+	frame = append(frame, body...)
+	frame = append(frame, checksum(frame), maxiiotEnd)
+	return frame
+}
+
+// checksum returns sum of bytes
+func checksum(b []byte) byte {
+	var sum byte
+	for _, v := range b {
+		sum += v
+	}
+	return sum
+}
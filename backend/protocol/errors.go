@@ -0,0 +1,55 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FieldError defines decode error of a frame field
+type FieldError struct {
+	Field string // 出错的字段
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// fieldErrorf returns field error with formatted message
+func fieldErrorf(field, format string, args ...interface{}) error {
+	return &FieldError{Field: field, Err: fmt.Errorf(format, args...)}
+}
+
+// 帧校验错误
+var (
+	ErrBadHeader       = errors.New("bad header")        // 帧起始或结束标志错误
+	ErrBadLength       = errors.New("bad length")        // 帧长度错误
+	ErrBadCRC          = errors.New("bad crc")           // 校验码错误
+	ErrUnknownDeviceID = errors.New("unknown device id") // 未知设备ID
+)
+
+// frameErrorf returns field error wrapping frame validation error
+func frameErrorf(field string, err error, format string, args ...interface{}) error {
+	return &FieldError{Field: field, Err: fmt.Errorf("%w: %s", err, fmt.Sprintf(format, args...))}
+}
+
+// RejectReason returns reason of frame rejected by validation, empty if err is not a validation error
+func RejectReason(err error) string {
+	switch {
+	case errors.Is(err, ErrBadHeader):
+		return "bad_header"
+	case errors.Is(err, ErrBadLength):
+		return "bad_length"
+	case errors.Is(err, ErrBadCRC):
+		return "bad_crc"
+	case errors.Is(err, ErrUnknownDeviceID):
+		return "unknown_device_id"
+	default:
+		return ""
+	}
+}
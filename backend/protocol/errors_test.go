@@ -0,0 +1,39 @@
+package protocol
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestRejectReason(t *testing.T) {
+	tests := []struct {
+		protocol string
+		frame    string
+		reason   string
+	}{
+		{"maxiiot", "1800000601020200406381", ""},
+		{"maxiiot", "1800000601020200406481", "bad_crc"},
+		{"maxiiot", "1900000601020200406381", "bad_header"},
+		{"maxiiot", "1800000701020200406481", "unknown_device_id"},
+		{"smoke", "1800000601001f", "bad_length"},
+		{"angus", "aa5cac117c0158a42e06ca2e5c01015bffea01010467", "bad_crc"},
+		{"humiture", "fe0200015cc11b9401011706013b014aff", "bad_header"},
+		{"humiture", "ff0200015cc11b9401011706013b014aff", ""},
+		{"humiture", "ff0200015cc11b9401011706013b01", "bad_length"},
+		{"humiture", "ff0200015cc11b94010117", "bad_length"},
+		{"humiture", "ff0200015cc11b9401011706013b014afe", "bad_header"},
+		{"humiture", "ff0200025cc11b9401011706013b014aff", "bad_length"},
+		{"humiture", "ff015cc11b9417063b4a12ff", ""},
+		{"humiture", "ff015cc11b9417063b4a12", "bad_length"},
+		{"humiture", "ff015cc11b9417063b4a1200", "bad_header"},
+	}
+
+	for _, test := range tests {
+		data, _ := hex.DecodeString(test.frame)
+		dec, _ := Lookup(test.protocol)
+		_, err := dec.Decode(data)
+		if got := RejectReason(err); got != test.reason {
+			t.Errorf("%s %s: got reason %q, want %q (%v)", test.protocol, test.frame, got, test.reason, err)
+		}
+	}
+}
@@ -1,30 +1,35 @@
 package protocol
 
 import (
-	"bytes"
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"math"
+	"strings"
 	"time"
+)
 
-	"github.com/pkg/errors"
+const (
+	humitureEnd      byte = 0xff // 帧结束标志
+	humitureAlarmLen      = 12   // 0xff01报警帧长度
 )
 
 // Humiture defines the humiture item
 type Humiture struct {
-	Temperature float64   `json:"temperature"`
-	Humidity    float64   `json:"humidity"`
-	Electricity float64   `json:"electricity"`
-	DateTime    time.Time `json:"date_time"`
-	alarm       *humitureAlarm
+	Temperature float64        `json:"temperature"`
+	Humidity    float64        `json:"humidity"`
+	Electricity float64        `json:"electricity"`
+	DateTime    time.Time      `json:"date_time"`
+	Alarm       *HumitureAlarm `json:"alarm,omitempty"` // 阈值报警,仅0xff01帧携带
 }
 
-type humitureAlarm struct {
-	humidityLow     bool
-	humidityHigh    bool
-	temperatureLow  bool
-	temperatureHigh bool
-	electricityLow  bool
+// HumitureAlarm 温湿度阈值报警
+type HumitureAlarm struct {
+	HumidityHigh    bool `json:"humidity_high"`    // 湿度过高
+	HumidityLow     bool `json:"humidity_low"`     // 湿度过低
+	TemperatureHigh bool `json:"temperature_high"` // 温度过高
+	TemperatureLow  bool `json:"temperature_low"`  // 温度过低
+	ElectricityLow  bool `json:"electricity_low"`  // 电量过低
 }
 
 // Humitures defines humiture list
@@ -41,43 +46,68 @@ func (h *Humiture) Marshal() []byte {
 	return b
 }
 
-func (ha *humitureAlarm) unmarshal(alarm byte) {
+func (ha *HumitureAlarm) unmarshal(alarm byte) {
 	if alarm&0x01 == 0x01 {
-		ha.humidityHigh = true
+		ha.HumidityHigh = true
 	}
 	if alarm&0x02 == 0x02 {
-		ha.temperatureHigh = true
+		ha.TemperatureHigh = true
 	}
 	if alarm&0x04 == 0x04 {
-		ha.humidityLow = true
+		ha.HumidityLow = true
 	}
 	if alarm&0x08 == 0x08 {
-		ha.temperatureLow = true
+		ha.TemperatureLow = true
 	}
 	if alarm&0x10 == 0x10 {
-		ha.electricityLow = true
+		ha.ElectricityLow = true
+	}
+}
+
+func (ha *HumitureAlarm) marshal() byte {
+	var alarm byte
+	if ha.HumidityHigh {
+		alarm |= 0x01
+	}
+	if ha.TemperatureHigh {
+		alarm |= 0x02
+	}
+	if ha.HumidityLow {
+		alarm |= 0x04
+	}
+	if ha.TemperatureLow {
+		alarm |= 0x08
+	}
+	if ha.ElectricityLow {
+		alarm |= 0x10
 	}
+	return alarm
 }
 
-// String returns humiture alarm
-func (ha *humitureAlarm) String() string {
-	buf := bytes.NewBufferString("")
-	if ha.humidityHigh {
-		buf.WriteString("湿度过高;")
+// Names returns english names of active alarms
+func (ha HumitureAlarm) Names() []string {
+	var names []string
+	if ha.HumidityHigh {
+		names = append(names, "humidity_high")
 	}
-	if ha.humidityLow {
-		buf.WriteString("湿度过低;")
+	if ha.HumidityLow {
+		names = append(names, "humidity_low")
 	}
-	if ha.temperatureHigh {
-		buf.WriteString("温度过高;")
+	if ha.TemperatureHigh {
+		names = append(names, "temperature_high")
 	}
-	if ha.temperatureLow {
-		buf.WriteString("温度过低;")
+	if ha.TemperatureLow {
+		names = append(names, "temperature_low")
 	}
-	if ha.electricityLow {
-		buf.WriteString("电量过低;")
+	if ha.ElectricityLow {
+		names = append(names, "electricity_low")
 	}
-	return string(buf.Bytes())
+	return names
+}
+
+// String returns active alarms separated by ;
+func (ha HumitureAlarm) String() string {
+	return strings.Join(ha.Names(), ";")
 }
 
 // Unmarshal defines unmarshal data to humitures
@@ -95,7 +125,7 @@ func (h *Humitures) Unmarshal(b []byte) (err error) {
 	)
 
 	if lengthB < 10 {
-		return errors.Errorf("数据帧长度小于10")
+		return frameErrorf("payload", ErrBadLength, "数据帧长度小于10")
 	}
 	if b[0] == 0xff && b[1] == 0x02 {
 		var (
@@ -105,13 +135,13 @@ func (h *Humitures) Unmarshal(b []byte) (err error) {
 			eles    []int8
 		)
 		start += 2
-		len := int(binary.BigEndian.Uint16(b[start : start+2]))
+		count := int(binary.BigEndian.Uint16(b[start : start+2]))
 		start += 2
-		h.Hums = make([]Humiture, 0, len)
-		tempInt = make([]int8, 0, len)
-		tempDec = make([]int8, 0, len)
-		hums = make([]int8, 0, len)
-		eles = make([]int8, 0, len)
+		h.Hums = make([]Humiture, 0, count)
+		tempInt = make([]int8, 0, count)
+		tempDec = make([]int8, 0, count)
+		hums = make([]int8, 0, count)
+		eles = make([]int8, 0, count)
 
 		ti := int64(binary.BigEndian.Uint32(b[start : start+4]))
 		start += 4
@@ -120,7 +150,7 @@ func (h *Humitures) Unmarshal(b []byte) (err error) {
 		templen2 := int(b[start]) + templen1
 		start++
 		if lengthB < templen2 {
-			return errors.Errorf("数据总长度小于温度长度字节")
+			return frameErrorf("temperature", ErrBadLength, "数据总长度小于温度长度字节")
 		}
 		var _tempInt int8
 		for start < templen1 {
@@ -148,10 +178,13 @@ func (h *Humitures) Unmarshal(b []byte) (err error) {
 			start++
 		}
 
+		if lengthB <= templen2 {
+			return frameErrorf("humidity", ErrBadLength, "数据总长度小于湿度长度字节")
+		}
 		humlen := templen2 + int(b[start]) + 1
 		start++
 		if lengthB < humlen {
-			return errors.Errorf("数据总长度小于湿度长度字节")
+			return frameErrorf("humidity", ErrBadLength, "数据总长度小于湿度长度字节")
 		}
 		var _hum int8
 		for start < humlen {
@@ -166,8 +199,17 @@ func (h *Humitures) Unmarshal(b []byte) (err error) {
 			start++
 		}
 
+		if lengthB <= humlen {
+			return frameErrorf("electricity", ErrBadLength, "数据总长度小于电量长度字节")
+		}
 		elelen := humlen + int(b[start]) + 1
 		start++
+		if lengthB != elelen+1 {
+			return frameErrorf("payload", ErrBadLength, "数据帧长度应为%d", elelen+1)
+		}
+		if b[elelen] != humitureEnd {
+			return frameErrorf("end", ErrBadHeader, "humiture frame end with 0x%02x", humitureEnd)
+		}
 		var _ele int8
 		for start < elelen {
 			if b[start]&0xa0 == 0xa0 {
@@ -181,7 +223,10 @@ func (h *Humitures) Unmarshal(b []byte) (err error) {
 			start++
 		}
 
-		for i := 0; i < len; i++ {
+		if len(tempInt) < count || len(tempDec) < count || len(hums) < count || len(eles) < count {
+			return frameErrorf("payload", ErrBadLength, "采样数据少于采样数量%d", count)
+		}
+		for i := 0; i < count; i++ {
 			h.Hums = append(h.Hums, Humiture{
 				Temperature: float64(tempInt[i]) + float64(tempDec[i])/10.,
 				Humidity:    float64(hums[i]),
@@ -191,6 +236,12 @@ func (h *Humitures) Unmarshal(b []byte) (err error) {
 			ti += 60
 		}
 	} else if b[0] == 0xff && b[1] == 0x01 {
+		if lengthB != humitureAlarmLen {
+			return frameErrorf("payload", ErrBadLength, "报警帧长度应为%d", humitureAlarmLen)
+		}
+		if b[lengthB-1] != humitureEnd {
+			return frameErrorf("end", ErrBadHeader, "humiture frame end with 0x%02x", humitureEnd)
+		}
 		start += 2
 		var (
 			ti      int64
@@ -210,7 +261,7 @@ func (h *Humitures) Unmarshal(b []byte) (err error) {
 		ele = int8(b[start])
 		start++
 		alarm := b[start]
-		alarminfo := &humitureAlarm{}
+		alarminfo := &HumitureAlarm{}
 		alarminfo.unmarshal(alarm)
 		h.Hums = []Humiture{
 			Humiture{
@@ -218,9 +269,161 @@ func (h *Humitures) Unmarshal(b []byte) (err error) {
 				Humidity:    float64(hum),
 				Electricity: float64(ele),
 				DateTime:    time.Unix(ti, 0),
-				alarm:       alarminfo,
+				Alarm:       alarminfo,
 			},
 		}
+	} else {
+		return frameErrorf("header", ErrBadHeader, "humiture frame start with 0xff01 or 0xff02")
 	}
 	return nil
 }
+
+// Marshal encode humitures, single sample with alarm is encoded as 0xff01 frame,
+// others as 0xff02 frame whose samples must be 60 seconds apart
+func (h *Humitures) Marshal() ([]byte, error) {
+	if len(h.Hums) == 0 {
+		return nil, fieldErrorf("payload", "humitures is empty")
+	}
+
+	if len(h.Hums) == 1 && h.Hums[0].Alarm != nil {
+		hum := h.Hums[0]
+		tempInt, tempDec := splitTemperature(hum.Temperature)
+		b := []byte{0xff, 0x01, 0, 0, 0, 0}
+		binary.BigEndian.PutUint32(b[2:6], uint32(hum.DateTime.Unix()))
+		b = append(b, byte(tempInt), byte(tempDec), byte(int8(math.Round(hum.Humidity))),
+			byte(int8(math.Round(hum.Electricity))), hum.Alarm.marshal(), humitureEnd)
+		return b, nil
+	}
+
+	if len(h.Hums) > 0xffff {
+		return nil, fieldErrorf("payload", "humitures count must <=65535")
+	}
+	var tempInt, tempDec, hums, eles []int8
+	for i, hum := range h.Hums {
+		if i > 0 && hum.DateTime.Unix()-h.Hums[i-1].DateTime.Unix() != 60 {
+			return nil, fieldErrorf("date_time", "humitures must be 60 seconds apart")
+		}
+		ti, td := splitTemperature(hum.Temperature)
+		tempInt = append(tempInt, ti)
+		tempDec = append(tempDec, td)
+		hums = append(hums, int8(math.Round(hum.Humidity)))
+		eles = append(eles, int8(math.Round(hum.Electricity)))
+	}
+
+	fields := make([][]byte, 0, 4)
+	for _, v := range []struct {
+		name   string
+		values []int8
+	}{{"temperature", tempInt}, {"temperature", tempDec}, {"humidity", hums}, {"electricity", eles}} {
+		field, err := compressHumiture(v.values)
+		if err != nil {
+			return nil, &FieldError{Field: v.name, Err: err}
+		}
+		fields = append(fields, field)
+	}
+
+	b := []byte{0xff, 0x02, 0, 0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint16(b[2:4], uint16(len(h.Hums)))
+	binary.BigEndian.PutUint32(b[4:8], uint32(h.Hums[0].DateTime.Unix()))
+	b = append(b, byte(len(fields[0])), byte(len(fields[1])))
+	b = append(b, fields[0]...)
+	b = append(b, fields[1]...)
+	for _, field := range fields[2:] {
+		b = append(b, byte(len(field)))
+		b = append(b, field...)
+	}
+	return append(b, humitureEnd), nil
+}
+
+// splitTemperature split temperature to integer and decimal part
+func splitTemperature(t float64) (int8, int8) {
+	t10 := int(math.Round(t * 10))
+	return int8(t10 / 10), int8(t10 % 10)
+}
+
+// compressHumiture encode values, repeats of previous value are encoded as 0xa0|count
+func compressHumiture(values []int8) ([]byte, error) {
+	var (
+		b    []byte
+		prev int8
+	)
+	for i := 0; i < len(values); {
+		n := 0
+		for i+n < len(values) && values[i+n] == prev && n < 0x0f {
+			n++
+		}
+		if n > 0 {
+			b = append(b, 0xa0|byte(n))
+			i += n
+			continue
+		}
+		if byte(values[i])&0xa0 == 0xa0 {
+			return nil, fmt.Errorf("value %d can not be encoded", values[i])
+		}
+		prev = values[i]
+		b = append(b, byte(prev))
+		i++
+	}
+	if len(b) > 0xff {
+		return nil, fmt.Errorf("encoded length must <=255")
+	}
+	return b, nil
+}
+
+// Alarms returns threshold alarms of humitures
+func (h Humitures) Alarms() []Alarm {
+	var alarms []Alarm
+	for _, hum := range h.Hums {
+		if hum.Alarm == nil {
+			continue
+		}
+		for _, name := range hum.Alarm.Names() {
+			alarms = append(alarms, Alarm{Name: name, DateTime: hum.DateTime})
+		}
+	}
+	return alarms
+}
+
+// Normal returns whether 0xff01 frame reports no threshold alarm
+func (h Humitures) Normal() bool {
+	for _, hum := range h.Hums {
+		if hum.Alarm == nil || len(hum.Alarm.Names()) > 0 {
+			return false
+		}
+	}
+	return len(h.Hums) > 0
+}
+
+// Measurements returns humiture measurements, each sample keeps its own time,
+// active threshold alarms are published as alarm field
+func (h Humitures) Measurements() []Measurement {
+	ms := make([]Measurement, 0, len(h.Hums)*3)
+	for _, hum := range h.Hums {
+		ms = append(ms,
+			Measurement{Field: "temp", Value: hum.Temperature, DateTime: hum.DateTime},
+			Measurement{Field: "hum", Value: hum.Humidity, DateTime: hum.DateTime},
+			Measurement{Field: "ele", Value: hum.Electricity, DateTime: hum.DateTime},
+		)
+		if hum.Alarm != nil && len(hum.Alarm.Names()) > 0 {
+			ms = append(ms, Measurement{Field: "alarm", Value: hum.Alarm.String(), DateTime: hum.DateTime})
+		}
+	}
+	return ms
+}
+
+func decodeHumiture(data []byte) (Decoded, error) {
+	var hums Humitures
+	if err := hums.Unmarshal(data); err != nil {
+		return Decoded{}, err
+	}
+	return Decoded{Object: hums, Measurements: hums.Measurements()}, nil
+}
+
+func init() {
+	Register(Decoder{
+		Name:        "humiture",
+		Description: "Maxiiot humiture sensor",
+		Fields:      []string{"temp", "hum", "ele", "alarm"},
+		Decode:      decodeHumiture,
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"testing"
+	"time"
 )
 
 func Test_Humitureunmarshal(t *testing.T) {
@@ -19,3 +20,29 @@ func Test_Humitureunmarshal(t *testing.T) {
 	j, _ := json.MarshalIndent(hums, "", " ")
 	t.Log(string(j))
 }
+
+func TestHumitureAlarms(t *testing.T) {
+	hums := Humitures{Hums: []Humiture{{
+		Temperature: 40.5,
+		Humidity:    20,
+		Electricity: 10,
+		DateTime:    time.Unix(1556000000, 0),
+		Alarm:       &HumitureAlarm{TemperatureHigh: true, ElectricityLow: true},
+	}}}
+	b, err := hums.Marshal()
+	if err != nil {
+		t.Fatal("marshal error:", err)
+	}
+
+	dec, _ := Lookup("humiture")
+	decoded, err := dec.Decode(b)
+	if err != nil {
+		t.Fatal("decode error:", err)
+	}
+	alarms := decoded.Object.(Humitures).Alarms()
+	if len(alarms) != 2 || alarms[0].Name != "temperature_high" || alarms[1].Name != "electricity_low" {
+		t.Errorf("unexpected alarms: %v", alarms)
+	}
+	j, _ := json.Marshal(decoded.Measurements)
+	t.Log(string(j))
+}
@@ -0,0 +1,75 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestMarshalRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame string
+		value interface {
+			Unmarshal([]byte) error
+			Marshal() ([]byte, error)
+		}
+	}{
+		{"maxiiot", "1800000601020200406381", &MaxiiotPayload{}},
+		{"humiture", "ff0200015cc11b9401011706013b014aff", &Humitures{}},
+		{"angus", "aa5cac117c0158a42e06ca2e5c01015bffea01010466", &Angus{}},
+	}
+
+	for _, test := range tests {
+		data, _ := hex.DecodeString(test.frame)
+		if err := test.value.Unmarshal(data); err != nil {
+			t.Errorf("%s unmarshal error: %s", test.name, err)
+			continue
+		}
+		b, err := test.value.Marshal()
+		if err != nil {
+			t.Errorf("%s marshal error: %s", test.name, err)
+			continue
+		}
+		if !bytes.Equal(b, data) {
+			t.Errorf("%s round trip: got %x, want %s", test.name, b, test.frame)
+		}
+	}
+}
+
+func TestHumituresRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		ti := time.Unix(1556000000+r.Int63n(1e6), 0)
+		hums := Humitures{}
+		for j := 0; j <= r.Intn(30); j++ {
+			hums.Hums = append(hums.Hums, Humiture{
+				Temperature: float64(r.Intn(600)) / 10,
+				Humidity:    float64(r.Intn(3) * 30),
+				Electricity: float64(100 - r.Intn(2)),
+				DateTime:    ti.Add(time.Duration(j) * time.Minute),
+			})
+		}
+
+		b, err := hums.Marshal()
+		if err != nil {
+			t.Fatal("marshal error:", err)
+		}
+		var got Humitures
+		if err := got.Unmarshal(b); err != nil {
+			t.Fatalf("unmarshal %x error: %s", b, err)
+		}
+		if len(got.Hums) != len(hums.Hums) {
+			t.Fatalf("unmarshal %x: got %d humitures, want %d", b, len(got.Hums), len(hums.Hums))
+		}
+		for j := range got.Hums {
+			g, w := got.Hums[j], hums.Hums[j]
+			if int(g.Temperature*10+0.5) != int(w.Temperature*10+0.5) || g.Humidity != w.Humidity ||
+				g.Electricity != w.Electricity || !g.DateTime.Equal(w.DateTime) {
+				t.Fatalf("unmarshal %x: got %+v, want %+v", b, g, w)
+			}
+		}
+	}
+}
@@ -1,13 +1,14 @@
 package protocol
 
 import (
-	"errors"
 	"fmt"
+	"reflect"
 )
 
 // Payload define data field interface
 type Payload interface {
 	Unmarshal([]byte) error
+	Marshal() ([]byte, error)
 }
 
 // TransCode 传输码
@@ -19,7 +20,7 @@ type TransCode struct {
 
 // Unmarshal TransCode unamrshal
 func (tc *TransCode) Unmarshal(b byte) error {
-	if b&1<<7 == 1 {
+	if b&(1<<7) != 0 {
 		tc.Direction = "Server2Dev"
 	} else {
 		tc.Direction = "Dev2Server"
@@ -29,28 +30,97 @@ func (tc *TransCode) Unmarshal(b byte) error {
 	return nil
 }
 
+// Marshal TransCode marshal
+func (tc TransCode) Marshal() (byte, error) {
+	if tc.Direction != "Server2Dev" && tc.Direction != "Dev2Server" {
+		return 0, fieldErrorf("direction", "direction must be Server2Dev or Dev2Server")
+	}
+	if tc.Major&^(1<<6|1<<5|1<<4) != 0 || tc.Minor&^(1<<3|1<<2|1) != 0 {
+		return 0, fieldErrorf("transcode", "major or minor version out of range")
+	}
+	return tc.byte(), nil
+}
+
+// byte returns transcode byte
+func (tc TransCode) byte() byte {
+	var b byte
+	if tc.Direction == "Server2Dev" {
+		b = 1 << 7
+	}
+	return b | tc.Major&(1<<6|1<<5|1<<4) | tc.Minor&(1<<3|1<<2|1)
+}
+
+// maxiiotSensor defines sensor carried by maxiiot frame
+type maxiiotSensor struct {
+	Name     string         // 协议名称
+	DeviceID [2]byte        // 设备ID
+	Fields   []string       // 解析后可能输出的字段
+	New      func() Payload // 返回空的数据域
+}
+
+// maxiiotSensors sensors detected by device id of maxiiot frame
+var maxiiotSensors = []maxiiotSensor{
+	{Name: "smoke", DeviceID: [2]byte{0x00, 0x06}, Fields: []string{"smoke"}, New: func() Payload { return &Smoke{} }},
+}
+
+// lookupMaxiiotSensor returns sensor by device id
+func lookupMaxiiotSensor(deviceID [2]byte) (maxiiotSensor, bool) {
+	for _, s := range maxiiotSensors {
+		if s.DeviceID == deviceID {
+			return s, true
+		}
+	}
+	return maxiiotSensor{}, false
+}
+
+// maxiiotDeviceID returns device id of sensor protocol name
+func maxiiotDeviceID(name string) ([2]byte, bool) {
+	for _, s := range maxiiotSensors {
+		if s.Name == name {
+			return s.DeviceID, true
+		}
+	}
+	return [2]byte{}, false
+}
+
 // MaxiiotPayload define maxiiot device payload
 type MaxiiotPayload struct {
 	Header     byte       // 帧起始标志
 	TransCode  *TransCode // 传输码
 	DeviceID   [2]byte    // 设备ID
+	SensorType string     // 根据设备ID识别的传感器类型
 	SensorData Payload    // 数据域
 	CRC        byte       // 校验码
 	End        byte       // 帧结束标志
 }
 
-// Unmarshal unmarshal maxiiot device
-func (p *MaxiiotPayload) Unmarshal(b []byte) (err error) {
+// Unmarshal unmarshal maxiiot device, sensor type detected by device id
+func (p *MaxiiotPayload) Unmarshal(b []byte) error {
+	if len(b) >= 4 {
+		sensor, ok := lookupMaxiiotSensor([2]byte{b[2], b[3]})
+		if ok {
+			p.SensorType = sensor.Name
+			return p.unmarshal(b, sensor.New())
+		}
+	}
+	if err := p.unmarshal(b, nil); err != nil {
+		return err
+	}
+	return frameErrorf("device_id", ErrUnknownDeviceID, "%x", p.DeviceID)
+}
+
+// unmarshal unmarshal maxiiot envelope and sensor data, sensor data is skipped when nil
+func (p *MaxiiotPayload) unmarshal(b []byte, sensor Payload) (err error) {
 	defer func() {
 		if res := recover(); res != nil {
 			err = fmt.Errorf("panic: %v", res)
 		}
 	}()
 
-	length := len(b)
-	if length < 9 {
-		return errors.New("unspoorts maxiiot device protocol")
+	if err := validateMaxiiotFrame(b); err != nil {
+		return err
 	}
+	length := len(b)
 	flag := 0
 	p.Header = b[flag]
 	flag++
@@ -60,16 +130,120 @@ func (p *MaxiiotPayload) Unmarshal(b []byte) (err error) {
 	flag++
 	copy(p.DeviceID[:], b[flag:flag+2])
 	flag += 2
-	switch p.DeviceID {
-	case [2]byte{0x00, 0x06}:
-		smoke := &Smoke{}
-		if err := smoke.Unmarshal(b[flag : length-2]); err != nil {
+	if sensor != nil {
+		if err := sensor.Unmarshal(b[flag : length-2]); err != nil {
 			return err
 		}
-		p.SensorData = smoke
-	default:
-		return errors.New("unspoorts maxiiot device protocol")
+		p.SensorData = sensor
 	}
+	p.CRC = b[length-2]
+	p.End = b[length-1]
 
 	return nil
 }
+
+// validateMaxiiotFrame check length, header, end and crc of maxiiot frame
+func validateMaxiiotFrame(b []byte) error {
+	length := len(b)
+	if length < 9 {
+		return frameErrorf("payload", ErrBadLength, "maxiiot frame length must >=9")
+	}
+	if b[0] != maxiiotHeader {
+		return frameErrorf("header", ErrBadHeader, "maxiiot frame start with 0x%02x", maxiiotHeader)
+	}
+	if b[length-1] != maxiiotEnd {
+		return frameErrorf("end", ErrBadHeader, "maxiiot frame end with 0x%02x", maxiiotEnd)
+	}
+	if crc := checksum(b[:length-2]); crc != b[length-2] {
+		return frameErrorf("crc", ErrBadCRC, "got 0x%02x, want 0x%02x", b[length-2], crc)
+	}
+	return nil
+}
+
+// Marshal encode maxiiot frame, header, crc and end are always generated,
+// device id is detected by sensor data when empty
+func (p MaxiiotPayload) Marshal() ([]byte, error) {
+	if p.SensorData == nil {
+		return nil, fieldErrorf("sensor_data", "sensor data is required")
+	}
+	tc := TransCode{Direction: "Dev2Server"}
+	if p.TransCode != nil {
+		tc = *p.TransCode
+	}
+	if _, err := tc.Marshal(); err != nil {
+		return nil, err
+	}
+
+	deviceID := p.DeviceID
+	if deviceID == [2]byte{} {
+		for _, sensor := range maxiiotSensors {
+			if reflect.TypeOf(sensor.New()) == reflect.TypeOf(p.SensorData) {
+				deviceID = sensor.DeviceID
+				break
+			}
+		}
+	}
+	if deviceID == [2]byte{} {
+		return nil, frameErrorf("device_id", ErrUnknownDeviceID, "unsupported maxiiot device protocol")
+	}
+
+	body, err := p.SensorData.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return encodeMaxiiotFrame(tc, deviceID, body), nil
+}
+
+// Acknowledgements returns downlink acknowledgements of sensor data
+func (p MaxiiotPayload) Acknowledgements() []Ack {
+	if a, ok := p.SensorData.(Acknowledger); ok {
+		return a.Acknowledgements()
+	}
+	return nil
+}
+
+// Alarms returns alarms of sensor data
+func (p MaxiiotPayload) Alarms() []Alarm {
+	if a, ok := p.SensorData.(Alarmer); ok {
+		return a.Alarms()
+	}
+	return nil
+}
+
+// Normal returns whether sensor data reports normal state
+func (p MaxiiotPayload) Normal() bool {
+	if a, ok := p.SensorData.(Alarmer); ok {
+		return a.Normal()
+	}
+	return false
+}
+
+// measurer defines sensor data which can be normalized to measurements
+type measurer interface {
+	Measurements() []Measurement
+}
+
+func decodeMaxiiot(data []byte) (Decoded, error) {
+	var p MaxiiotPayload
+	if err := p.Unmarshal(data); err != nil {
+		return Decoded{}, err
+	}
+	decoded := Decoded{Object: p}
+	if m, ok := p.SensorData.(measurer); ok {
+		decoded.Measurements = m.Measurements()
+	}
+	return decoded, nil
+}
+
+func init() {
+	var fields []string
+	for _, sensor := range maxiiotSensors {
+		fields = append(fields, sensor.Fields...)
+	}
+	Register(Decoder{
+		Name:        "maxiiot",
+		Description: "Maxiiot frame, sensor type detected by device id",
+		Fields:      fields,
+		Decode:      decodeMaxiiot,
+	})
+}
@@ -0,0 +1,25 @@
+package protocol
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestMaxiiotDetectSensor(t *testing.T) {
+	data, err := hex.DecodeString("1800000601020200406381")
+	if err != nil {
+		t.Error("decode data error:", err)
+	}
+	dec, _ := Lookup("maxiiot")
+	decoded, err := dec.Decode(data)
+	if err != nil {
+		t.Fatal("maxiiot decode error:", err)
+	}
+	p := decoded.Object.(MaxiiotPayload)
+	if _, ok := p.SensorData.(*Smoke); !ok || p.SensorType != "smoke" {
+		t.Errorf("unexpected sensor %s: %T", p.SensorType, p.SensorData)
+	}
+	if len(decoded.Measurements) != 1 || decoded.Measurements[0].Field != "smoke" {
+		t.Errorf("unexpected measurements: %v", decoded.Measurements)
+	}
+}
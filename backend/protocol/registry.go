@@ -0,0 +1,128 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Measurement 解析后的单个测量值
+type Measurement struct {
+	Field    string      `json:"field"`               // 字段名,对应发布主题的最后一级
+	Value    interface{} `json:"value"`               // 测量值
+	DateTime time.Time   `json:"date_time,omitempty"` // 采样时间,为空时取接收时间
+	Format   string      `json:"-"`                   // 发布格式,浮点数默认%.1f
+}
+
+// String returns the measurement value in publish format
+func (m Measurement) String() string {
+	if m.Format != "" {
+		return fmt.Sprintf(m.Format, m.Value)
+	}
+	switch v := m.Value.(type) {
+	case float64:
+		return fmt.Sprintf("%.1f", v)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// MarshalJSON omit zero date time
+func (m Measurement) MarshalJSON() ([]byte, error) {
+	type alias Measurement
+	var dt *time.Time
+	if !m.DateTime.IsZero() {
+		dt = &m.DateTime
+	}
+	return json.Marshal(struct {
+		alias
+		DateTime *time.Time `json:"date_time,omitempty"`
+	}{alias(m), dt})
+}
+
+// Alarm 解析出的设备报警
+type Alarm struct {
+	Name     string    `json:"name"`                // 报警名称
+	DateTime time.Time `json:"date_time,omitempty"` // 报警时间,为空时取接收时间
+}
+
+// Alarmer defines decoded objects carrying device alarms
+type Alarmer interface {
+	Alarms() []Alarm
+	// Normal returns whether the frame reports device in normal state, e.g. heartbeat without alarm
+	Normal() bool
+}
+
+// Decoded defines the result of a decoder
+type Decoded struct {
+	Object       interface{}   `json:"object"`       // 协议解析后的原始结构
+	Measurements []Measurement `json:"measurements"` // 归一化后的测量值
+}
+
+// DecodeFunc decode device payload
+type DecodeFunc func(data []byte) (Decoded, error)
+
+// Decoder defines a registered device protocol
+type Decoder struct {
+	Name        string     `json:"name"`        // 协议名称,即设备的protocol_type
+	Description string     `json:"description"` // 协议描述
+	Fields      []string   `json:"fields"`      // 解析后可能输出的字段
+	Decode      DecodeFunc `json:"-"`
+}
+
+var (
+	mu       sync.RWMutex
+	decoders = make(map[string]Decoder)
+)
+
+// Register register decoder, panics if the name is empty or registered twice
+func Register(d Decoder) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if d.Name == "" || d.Decode == nil {
+		panic("protocol: decoder name and decode func are required")
+	}
+	if _, ok := decoders[d.Name]; ok {
+		panic(fmt.Sprintf("protocol: decoder %s registered twice", d.Name))
+	}
+	decoders[d.Name] = d
+}
+
+// Lookup returns the decoder registered by name
+func Lookup(name string) (Decoder, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	d, ok := decoders[name]
+	return d, ok
+}
+
+// Decoders returns all registered decoders order by name
+func Decoders() []Decoder {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	list := make([]Decoder, 0, len(decoders))
+	for _, d := range decoders {
+		list = append(list, d)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Names returns all registered decoder names order by name
+func Names() []string {
+	list := Decoders()
+	names := make([]string, 0, len(list))
+	for _, d := range list {
+		names = append(names, d.Name)
+	}
+	return names
+}
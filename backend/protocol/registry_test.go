@@ -0,0 +1,53 @@
+package protocol
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+)
+
+func TestRegistryDecode(t *testing.T) {
+	for _, name := range []string{"humiture", "smoke", "maxiiot", "angus"} {
+		if _, ok := Lookup(name); !ok {
+			t.Errorf("decoder %s not registered", name)
+		}
+	}
+
+	data, err := hex.DecodeString("1800000601020200406381")
+	if err != nil {
+		t.Error("decode data error:", err)
+	}
+	dec, _ := Lookup("smoke")
+	decoded, err := dec.Decode(data)
+	if err != nil {
+		t.Error("smoke decode error:", err)
+	}
+	if len(decoded.Measurements) != 1 || decoded.Measurements[0].Field != "smoke" {
+		t.Errorf("unexpected smoke measurements: %v", decoded.Measurements)
+	}
+	js, _ := json.MarshalIndent(decoded, "", " ")
+	t.Log(string(js))
+
+	// 发布的测量字段必须在解析器声明的字段中
+	data, err = hex.DecodeString("aa5cac117c0158a42e06ca2e5c01015bffea01010466")
+	if err != nil {
+		t.Error("decode data error:", err)
+	}
+	dec, _ = Lookup("angus")
+	decoded, err = dec.Decode(data)
+	if err != nil {
+		t.Fatal("angus decode error:", err)
+	}
+	fields := make(map[string]bool, len(dec.Fields))
+	for _, f := range dec.Fields {
+		fields[f] = true
+	}
+	for _, m := range decoded.Measurements {
+		if !fields[m.Field] {
+			t.Errorf("angus measurement %s not in decoder fields %v", m.Field, dec.Fields)
+		}
+	}
+	if len(decoded.Measurements) != 6 || decoded.Measurements[5].Field != "alarm" || decoded.Measurements[5].String() != "remove" {
+		t.Errorf("unexpected angus measurements: %v", decoded.Measurements)
+	}
+}
@@ -1,7 +1,10 @@
 package protocol
 
 import (
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 // SmokeAlarm 烟雾报警类型
@@ -28,7 +31,52 @@ var (
 	SmokeAlarmHighSensitivity = [2]byte{0x01, 0x00}
 )
 
+// Conditions returns every active condition of the bitmask,
+// unknown bits are returned as separate conditions too
+func (sa SmokeAlarm) Conditions() []SmokeAlarm {
+	var list []SmokeAlarm
+	mask := binary.BigEndian.Uint16(sa[:])
+	for i := uint(0); i < 16; i++ {
+		if mask&(1<<i) == 0 {
+			continue
+		}
+		var c SmokeAlarm
+		binary.BigEndian.PutUint16(c[:], 1<<i)
+		list = append(list, c)
+	}
+	return list
+}
+
+// Has returns whether condition is active
+func (sa SmokeAlarm) Has(c SmokeAlarm) bool {
+	return sa[0]&c[0] == c[0] && sa[1]&c[1] == c[1]
+}
+
+// Names returns names of active conditions
+func (sa SmokeAlarm) Names() []string {
+	conditions := sa.Conditions()
+	names := make([]string, 0, len(conditions))
+	for _, c := range conditions {
+		names = append(names, c.Name())
+	}
+	return names
+}
+
+// String returns descriptions of active conditions separated by ;
 func (sa SmokeAlarm) String() string {
+	conditions := sa.Conditions()
+	if len(conditions) == 0 {
+		return "normal"
+	}
+	descs := make([]string, 0, len(conditions))
+	for _, c := range conditions {
+		descs = append(descs, c.description())
+	}
+	return strings.Join(descs, ";")
+}
+
+// description returns description of single condition
+func (sa SmokeAlarm) description() string {
 	switch sa {
 	case SmokeAlarmSmoke:
 		return "Smoke alarm"
@@ -53,14 +101,58 @@ func (sa SmokeAlarm) String() string {
 	}
 }
 
-func (sa SmokeAlarm) MarshalText() ([]byte, error) {
-	return []byte(sa.String()), nil
+// Name returns name of single condition used by alarm records
+func (sa SmokeAlarm) Name() string {
+	switch sa {
+	case SmokeAlarmSmoke:
+		return "smoke"
+	case SmokeAlarmHightTemp:
+		return "high_temperature"
+	case SmokeAlarmSmokeAndHightTemp:
+		return "smoke_and_high_temperature"
+	case SmokeAlarmSensorFail:
+		return "smoke_sensor_failure"
+	case SmokeAlarmHightTempSensorFail:
+		return "high_temperature_sensor_failure"
+	case SmokeAlarmHSSensorFail:
+		return "high_temperature_and_smoke_sensor_failure"
+	case SmokeAlarmLowerEle:
+		return "low_battery"
+	case SmokeAlarmLowSensitivity:
+		return "low_sensitivity"
+	case SmokeAlarmHighSensitivity:
+		return "high_sensitivity"
+	default:
+		return fmt.Sprintf("unknown_%x", sa[:])
+	}
+}
+
+// MarshalJSON returns names of active conditions
+func (sa SmokeAlarm) MarshalJSON() ([]byte, error) {
+	return json.Marshal(sa.Names())
+}
+
+// Ack 设备对下行指令的应答
+type Ack struct {
+	Code   uint8 `json:"code"`   // 应答的下行功能码
+	Result uint8 `json:"result"` // 执行结果,0x00为成功
+}
+
+// Success returns whether the device executed the command
+func (a Ack) Success() bool {
+	return a.Result == 0x00
+}
+
+// Acknowledger defines decoded objects carrying downlink acknowledgements
+type Acknowledger interface {
+	Acknowledgements() []Ack
 }
 
 // Smoke smoke device handler
 type Smoke struct {
 	IsHeartBeat bool        `json:"is_heartbeat"`
 	Alarm       *SmokeAlarm `json:"alarm,omitempty"`
+	Acks        []Ack       `json:"acks,omitempty"`
 }
 
 // Unmarshal decode data to struct
@@ -85,10 +177,19 @@ func (s *Smoke) Unmarshal(data []byte) (err error) {
 			start++
 			start += dataLen
 
-		case 0x01: // 下行应答
+		case 0x01: // 下行应答,数据为应答的功能码和执行结果
 			start++
 			dataLen := int(data[start])
 			start++
+			if dataLen == 0 {
+				return fieldErrorf("ack", "ack data is empty")
+			}
+			data := data[start : start+dataLen]
+			ack := Ack{Code: data[0]}
+			if dataLen > 1 {
+				ack.Result = data[1]
+			}
+			s.Acks = append(s.Acks, ack)
 			start += dataLen
 		case 0x02: // 烟雾报警上报
 			start++
@@ -108,3 +209,84 @@ func (s *Smoke) Unmarshal(data []byte) (err error) {
 	}
 	return
 }
+
+// Marshal encode heartbeat, acks and alarm function codes
+func (s *Smoke) Marshal() ([]byte, error) {
+	n := len(s.Acks)
+	if s.IsHeartBeat {
+		n++
+	}
+	if s.Alarm != nil {
+		n++
+	}
+	if n == 0 || n > 0xff {
+		return nil, fieldErrorf("functions", "functions count must be 1-255")
+	}
+
+	b := []byte{byte(n)}
+	if s.IsHeartBeat {
+		b = append(b, 0x00, 0x00)
+	}
+	for _, ack := range s.Acks {
+		b = append(b, 0x01, 0x02, ack.Code, ack.Result)
+	}
+	if s.Alarm != nil {
+		b = append(b, 0x02, 0x02, s.Alarm[0], s.Alarm[1])
+	}
+	return b, nil
+}
+
+// Acknowledgements returns downlink acknowledgements of the frame
+func (s Smoke) Acknowledgements() []Ack {
+	return s.Acks
+}
+
+// Alarms returns every active smoke alarm condition
+func (s Smoke) Alarms() []Alarm {
+	if s.Alarm == nil {
+		return nil
+	}
+	var alarms []Alarm
+	for _, name := range s.Alarm.Names() {
+		alarms = append(alarms, Alarm{Name: name})
+	}
+	return alarms
+}
+
+// Normal returns whether the frame is heartbeat or alarm report without active condition
+func (s Smoke) Normal() bool {
+	return len(s.Alarms()) == 0 && (s.IsHeartBeat || s.Alarm != nil)
+}
+
+// Measurements returns smoke heartbeat and alarm measurements
+func (s Smoke) Measurements() []Measurement {
+	var ms []Measurement
+	if s.IsHeartBeat {
+		ms = append(ms, Measurement{Field: "smoke", Value: "heartbeat"})
+	}
+	if s.Alarm != nil {
+		ms = append(ms, Measurement{Field: "smoke", Value: s.Alarm.String()})
+	}
+	return ms
+}
+
+// decodeSmoke decode maxiiot frame as smoke regardless of device id
+func decodeSmoke(data []byte) (Decoded, error) {
+	var (
+		p     MaxiiotPayload
+		smoke Smoke
+	)
+	if err := p.unmarshal(data, &smoke); err != nil {
+		return Decoded{}, err
+	}
+	return Decoded{Object: smoke, Measurements: smoke.Measurements()}, nil
+}
+
+func init() {
+	Register(Decoder{
+		Name:        "smoke",
+		Description: "Maxiiot smoke detector",
+		Fields:      []string{"smoke"},
+		Decode:      decodeSmoke,
+	})
+}
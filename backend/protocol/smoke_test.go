@@ -21,3 +21,54 @@ func TestSmokeUnmarshal(t *testing.T) {
 	js, _ := json.MarshalIndent(s, "", " ")
 	t.Log(string(js))
 }
+
+func TestEncodeCommand(t *testing.T) {
+	b, err := EncodeCommand("smoke", Command{Functions: []CommandFunction{{Code: 0x02, Data: "0040"}}})
+	if err != nil {
+		t.Error("encode command error:", err)
+	}
+	// 与上行示例相同的功能码,方向位不同
+	if got := hex.EncodeToString(b); got != "188000060102020040e381" {
+		t.Errorf("unexpected frame: %s", got)
+	}
+}
+
+func TestSmokeAck(t *testing.T) {
+	data, err := hex.DecodeString("1800000601010202002281")
+	if err != nil {
+		t.Error("decode data error:", err)
+	}
+
+	s := Smoke{}
+	if err = s.Unmarshal(data[4 : len(data)-2]); err != nil {
+		t.Error("smoke unmarshal error:", err)
+	}
+	if len(s.Acks) != 1 || s.Acks[0].Code != 0x02 || !s.Acks[0].Success() {
+		t.Errorf("unexpected acks: %+v", s.Acks)
+	}
+}
+
+func TestSmokeAlarms(t *testing.T) {
+	s := Smoke{IsHeartBeat: true}
+	if !s.Normal() || len(s.Alarms()) != 0 {
+		t.Error("heartbeat without alarm should be normal")
+	}
+	alarm := SmokeAlarm(SmokeAlarmLowerEle)
+	s.Alarm = &alarm
+	if s.Normal() || len(s.Alarms()) != 1 || s.Alarms()[0].Name != "low_battery" {
+		t.Errorf("unexpected alarms: %v", s.Alarms())
+	}
+}
+
+func TestSmokeAlarmBitmask(t *testing.T) {
+	alarm := SmokeAlarm{0x00, 0x48}
+	names := alarm.Names()
+	if len(names) != 2 || names[0] != "smoke_sensor_failure" || names[1] != "low_battery" {
+		t.Errorf("unexpected conditions: %v", names)
+	}
+	if !alarm.Has(SmokeAlarmLowerEle) || alarm.Has(SmokeAlarmSmoke) {
+		t.Error("unexpected condition check")
+	}
+	js, _ := json.Marshal(Smoke{Alarm: &alarm})
+	t.Log(string(js), alarm.String())
+}
@@ -0,0 +1,142 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/maxiiot/devicebridge/backend/protocol"
+	"github.com/maxiiot/devicebridge/storage"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// PublishModeScalar publish each field to device/<eui>/<field>
+	PublishModeScalar = "scalar"
+	// PublishModeEvent publish one json event to device/<eui>/event
+	PublishModeEvent = "event"
+	// PublishModeBoth publish scalar topics and json event
+	PublishModeBoth = "both"
+)
+
+// DefaultTopicTemplate default publish topic template
+const DefaultTopicTemplate = "device/{dev_eui}/{field}"
+
+// UngroupedPath {group_path} of device without group
+const UngroupedPath = "ungrouped"
+
+// PublishOptions defines how decoded uplinks are published
+type PublishOptions struct {
+	Mode          string   // 发布模式 scalar/event/both
+	TopicTemplate string   // 主题模板,支持{dev_eui},{protocol},{field},{application_id},{group_path}
+	QOS           uint8    // 发布QoS
+	RetainFields  []string // 需要retain的字段,event表示json事件
+}
+
+var (
+	publishOpts = PublishOptions{
+		Mode:          PublishModeScalar,
+		TopicTemplate: DefaultTopicTemplate,
+	}
+	retainFields = make(map[string]bool)
+)
+
+// SetPublishOptions set publish options, empty mode means scalar and empty template means default
+func SetPublishOptions(opts PublishOptions) error {
+	switch opts.Mode {
+	case "":
+		opts.Mode = PublishModeScalar
+	case PublishModeScalar, PublishModeEvent, PublishModeBoth:
+	default:
+		return fmt.Errorf("unsupported publish mode %s,optional(scalar/event/both)", opts.Mode)
+	}
+
+	if opts.TopicTemplate == "" {
+		opts.TopicTemplate = DefaultTopicTemplate
+	}
+	if !strings.Contains(opts.TopicTemplate, "{field}") {
+		return fmt.Errorf("publish topic template %s must contain {field}", opts.TopicTemplate)
+	}
+
+	if opts.QOS > 2 {
+		return fmt.Errorf("publish qos must be 0,1 or 2")
+	}
+
+	fields := make(map[string]bool, len(opts.RetainFields))
+	for _, field := range opts.RetainFields {
+		fields[field] = true
+	}
+
+	publishOpts = opts
+	retainFields = fields
+	return nil
+}
+
+// publishTopic render topic template of field
+func publishTopic(dev storage.Device, data DataUpPayloadChan, field string) string {
+	groupPath := dev.GroupPath
+	if groupPath == "" {
+		groupPath = UngroupedPath
+	}
+	r := strings.NewReplacer(
+		"{dev_eui}", data.DevEUI.String(),
+		"{protocol}", dev.ProtocolType,
+		"{field}", field,
+		"{application_id}", strconv.FormatInt(data.ApplicationID, 10),
+		"{group_path}", groupPath,
+	)
+	return r.Replace(publishOpts.TopicTemplate)
+}
+
+// UplinkEvent json event published per decoded uplink
+type UplinkEvent struct {
+	DevEUI       storage.EUI64          `json:"dev_eui"`
+	ProtocolType string                 `json:"protocol_type"`
+	Object       interface{}            `json:"object"`
+	Measurements []protocol.Measurement `json:"measurements"`
+	Alarms       []protocol.Alarm       `json:"alarms,omitempty"`
+	FPort        uint8                  `json:"fport"`
+	FCnt         uint32                 `json:"fcnt"`
+	RxMetadata   RxMetadata             `json:"rx_metadata"`
+	ServerTime   time.Time              `json:"server_time"`
+}
+
+func publishDecoded(conn paho.Client, dev storage.Device, data DataUpPayloadChan, decoded protocol.Decoded) {
+	mode := publishOpts.Mode
+	if mode == PublishModeScalar || mode == PublishModeBoth {
+		for _, m := range decoded.Measurements {
+			publish(conn, publishTopic(dev, data, m.Field), retainFields[m.Field], m.String())
+		}
+	}
+
+	if mode == PublishModeEvent || mode == PublishModeBoth {
+		event := UplinkEvent{
+			DevEUI:       data.DevEUI,
+			ProtocolType: dev.ProtocolType,
+			Object:       decoded.Object,
+			Measurements: decoded.Measurements,
+			Alarms:       decodedAlarms(decoded),
+			FPort:        data.FPort,
+			FCnt:         data.FCnt,
+			RxMetadata:   data.RxMetadata,
+			ServerTime:   time.Now(),
+		}
+		b, err := json.Marshal(event)
+		if err != nil {
+			log.WithError(err).WithField("device", data.DevEUI).Error("marshal uplink event error")
+			return
+		}
+		publish(conn, publishTopic(dev, data, "event"), retainFields["event"], string(b))
+	}
+}
+
+func publish(conn paho.Client, topic string, retained bool, msg string) {
+	if token := conn.Publish(topic, publishOpts.QOS, retained, msg); token.Wait() && token.Error() != nil {
+		log.WithError(token.Error()).Errorf("publish %s %s", topic, msg)
+	} else {
+		log.Infof("publish success,topic: %s msg: %s", topic, msg)
+	}
+}
@@ -0,0 +1,29 @@
+package backend
+
+import (
+	"testing"
+
+	"github.com/maxiiot/devicebridge/storage"
+)
+
+func TestPublishTopicGroupPath(t *testing.T) {
+	defer SetPublishOptions(PublishOptions{})
+	if err := SetPublishOptions(PublishOptions{TopicTemplate: "site/{group_path}/{dev_eui}/{field}"}); err != nil {
+		t.Fatal(err)
+	}
+
+	data := DataUpPayloadChan{DevEUI: storage.EUI64{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}}
+	tests := []struct {
+		groupPath string
+		want      string
+	}{
+		{"farm/barn-1", "site/farm/barn-1/0102030405060708/temp"},
+		{"", "site/ungrouped/0102030405060708/temp"},
+	}
+	for _, tt := range tests {
+		dev := storage.Device{DeviceEUI: data.DevEUI, GroupPath: tt.groupPath}
+		if got := publishTopic(dev, data, "temp"); got != tt.want {
+			t.Errorf("group path %q: got %s, want %s", tt.groupPath, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package backend
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/maxiiot/devicebridge/backend/protocol"
+	"github.com/maxiiot/devicebridge/storage"
+)
+
+// MaxReplayLimit max uplinks of one replay
+const MaxReplayLimit = 1000
+
+// ReplayOptions options of replaying stored uplinks,
+// dry run when neither Publish nor Rewrite is set
+type ReplayOptions struct {
+	DevEUI  *storage.EUI64 `json:"dev_eui"`
+	From    *time.Time     `json:"from"`
+	To      *time.Time     `json:"to"`
+	Limit   int            `json:"limit"`
+	Publish bool           `json:"publish"` // 重新发布到mqtt
+	Rewrite bool           `json:"rewrite"` // 重写测量值和解析结果
+}
+
+// ReplayResult decode result of stored uplink
+type ReplayResult struct {
+	UplinkID     int64             `json:"uplink_id"`
+	DevEUI       storage.EUI64     `json:"dev_eui"`
+	ProtocolType string            `json:"protocol_type"`
+	Data         storage.HexBytes  `json:"data"`
+	ReceivedAt   time.Time         `json:"received_at"`
+	Decoded      *protocol.Decoded `json:"decoded,omitempty"`
+	Error        string            `json:"error,omitempty"`
+}
+
+// Validate check replay options, device eui or time range is required
+func (opts ReplayOptions) Validate() error {
+	if opts.DevEUI == nil && opts.From == nil && opts.To == nil {
+		return errors.New("replay requires device eui or time range")
+	}
+	if opts.Limit <= 0 || opts.Limit > MaxReplayLimit {
+		return fmt.Errorf("replay limit must be >0 and <=%d", MaxReplayLimit)
+	}
+	return nil
+}
+
+// Replay re-run stored uplinks through the current decoders
+func Replay(conn paho.Client, opts ReplayOptions) ([]ReplayResult, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.Publish && conn == nil {
+		return nil, errors.New("replay publish requires mqtt publisher")
+	}
+
+	logs, err := storage.GetUplinkLogsInRange(opts.DevEUI, opts.From, opts.To, opts.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	devs := make(map[storage.EUI64]storage.Device)
+	results := make([]ReplayResult, 0, len(logs))
+	for _, l := range logs {
+		dev, ok := devs[l.DeviceEUI]
+		if !ok {
+			dev, err = storage.GetDeviceByEUI(l.DeviceEUI.String())
+			if err != nil {
+				// 设备已删除时按日志中的协议解析
+				dev = storage.Device{DeviceEUI: l.DeviceEUI, ProtocolType: l.ProtocolType}
+			}
+			devs[l.DeviceEUI] = dev
+		}
+
+		result := ReplayResult{
+			UplinkID:     l.ID,
+			DevEUI:       l.DeviceEUI,
+			ProtocolType: dev.ProtocolType,
+			Data:         l.Data,
+			ReceivedAt:   l.ReceivedAt,
+		}
+		decoded, status, err := replayUplink(conn, dev, l, opts)
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Decoded = &decoded
+		}
+
+		if opts.Rewrite && status != "" {
+			if e := storage.UpdateUplinkLogDecode(l.ID, status, result.Error); e != nil && result.Error == "" {
+				result.Error = e.Error()
+			}
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// replayUplink decode one stored uplink, returns the decode status to record
+func replayUplink(conn paho.Client, dev storage.Device, l storage.UplinkLog, opts ReplayOptions) (protocol.Decoded, string, error) {
+	decoder, ok := protocol.Lookup(dev.ProtocolType)
+	if !ok {
+		return protocol.Decoded{}, storage.DecodeStatusNoDecoder, errors.New("no decoder of protocol " + dev.ProtocolType)
+	}
+
+	decoded, err := decoder.Decode(l.Data)
+	if err != nil {
+		return decoded, storage.DecodeStatusFailed, err
+	}
+
+	if opts.Rewrite {
+		ms := toStorageMeasurements(l.DeviceEUI, decoded.Measurements, l.ReceivedAt)
+		if err := storage.ReplaceMeasurements(l.DeviceEUI, l.ReceivedAt, ms); err != nil {
+			return decoded, "", err
+		}
+	}
+
+	if opts.Publish {
+		data := DataUpPayloadChan{
+			Data:          l.Data,
+			DevEUI:        l.DeviceEUI,
+			ApplicationID: l.ApplicationID,
+			FPort:         l.FPort,
+			FCnt:          l.FCnt,
+		}
+		if len(l.RxMetadata) > 0 {
+			json.Unmarshal(l.RxMetadata, &data.RxMetadata)
+		}
+		publishDecoded(conn, dev, data, decoded)
+	}
+
+	return decoded, storage.DecodeStatusSuccess, nil
+}
@@ -17,6 +17,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/maxiiot/devicebridge/backend"
 	"github.com/maxiiot/devicebridge/backend/http"
 	"github.com/maxiiot/devicebridge/backend/mqtt"
 	"github.com/maxiiot/devicebridge/config"
@@ -42,11 +43,24 @@ type BackendServer interface {
 // Server 后台服务
 // support http/mqtt backend
 type Server struct {
-	backends  []BackendServer
-	wg        sync.WaitGroup
-	publisher paho.Client
+	backends           []BackendServer
+	wg                 sync.WaitGroup
+	publisher          paho.Client
+	uplinkLogRetention time.Duration
+	downlinker         backend.DownlinkSender
+	downlinkQueue      chan int64
+	offlineIntervals   int
+	done               chan struct{}
+	stopOnce           sync.Once
 }
 
+const (
+	// downlinkRetryInterval 下行数据重试间隔,每次失败后加倍
+	downlinkRetryInterval = 30 * time.Second
+	// maxDownlinkAttempts 下行数据最大发送次数,超过后标记为failed
+	maxDownlinkAttempts = 5
+)
+
 // NewServer return server point
 func NewServer(cfg config.Configuration) (*Server, error) {
 	backends := make([]BackendServer, 0, 2)
@@ -68,11 +82,29 @@ func NewServer(cfg config.Configuration) (*Server, error) {
 
 	backends = append(backends, httpserv)
 
-	conn, err := newPublisher(cfg.Publisher.Mqtt)
+	backend.SetDedupWindow(time.Duration(cfg.LoraBackend.DedupWindow) * time.Second)
+
+	conn, err := NewPublisher(cfg)
 	if err != nil {
 		return nil, err
 	}
-	serv := &Server{backends: backends, publisher: conn}
+	serv := &Server{
+		backends:           backends,
+		publisher:          conn,
+		uplinkLogRetention: time.Duration(cfg.UplinkLog.RetentionDays) * time.Hour * 24,
+		downlinkQueue:      make(chan int64, 100),
+		offlineIntervals:   cfg.Liveness.OfflineIntervals,
+		done:               make(chan struct{}),
+	}
+
+	switch cfg.Downlink.Mode {
+	case "", "mqtt":
+		serv.downlinker = mqttSer
+	case "http":
+		serv.downlinker = http.NewDownlinkClient(cfg.LoraServer.Server, cfg.LoraServer.UserName, cfg.LoraServer.Password)
+	default:
+		return nil, fmt.Errorf("unsupported downlink mode %s,optional(mqtt/http)", cfg.Downlink.Mode)
+	}
 
 	return serv, nil
 }
@@ -87,10 +119,21 @@ func (s *Server) Start() {
 		}(s.backends[idx])
 	}
 
+	if s.uplinkLogRetention > 0 {
+		go s.purgeUplinkLogs()
+	}
+
+	s.wg.Add(1)
+	go s.handleDownlinks()
+
+	if s.offlineIntervals > 0 {
+		go s.checkOffline()
+	}
 }
 
 // Stop 关闭相关资源
 func (s *Server) Stop() error {
+	s.stopOnce.Do(func() { close(s.done) })
 	for _, backend := range s.backends {
 		if err := backend.Close(); err != nil {
 			return err
@@ -108,6 +151,180 @@ func (s *Server) OnDeviceChange(notice map[string]bool) {
 	}
 }
 
+// EnqueueDownlink persist downlink and put it to send queue
+func (s *Server) EnqueueDownlink(dl storage.Downlink) (storage.Downlink, error) {
+	dl, err := storage.CreateDownlink(dl)
+	if err != nil {
+		return dl, err
+	}
+
+	select {
+	case s.downlinkQueue <- dl.ID:
+	default:
+		// 队列已满时保持pending,由定时重试发送
+		log.WithField("id", dl.ID).Warn("downlink queue is full, will be sent by retry")
+	}
+	return dl, nil
+}
+
+// handleDownlinks 发送下行数据队列,连接后先发送未完成的下行数据,并定时重试pending的下行数据
+func (s *Server) handleDownlinks() {
+	defer s.wg.Done()
+
+	for !s.downlinker.IsConnected() {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-s.done:
+			return
+		}
+	}
+	s.sendPendingDownlinks()
+
+	ticker := time.NewTicker(downlinkRetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case id := <-s.downlinkQueue:
+			s.sendDownlink(id)
+		case <-ticker.C:
+			s.sendPendingDownlinks()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// sendPendingDownlinks 发送已到重试时间的pending下行数据
+func (s *Server) sendPendingDownlinks() {
+	if !s.downlinker.IsConnected() {
+		return
+	}
+
+	ids, err := storage.GetPendingDownlinkIDs(time.Now())
+	if err != nil {
+		log.WithError(err).Error("get pending downlinks error")
+		return
+	}
+	for _, id := range ids {
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+		s.sendDownlink(id)
+	}
+}
+
+func (s *Server) sendDownlink(id int64) {
+	dl, err := storage.GetDownlink(id)
+	if err != nil {
+		log.WithError(err).WithField("id", id).Error("get downlink error")
+		return
+	}
+	if dl.Status != storage.DownlinkStatusPending {
+		return
+	}
+	if dl.RetryAt != nil && dl.RetryAt.After(time.Now()) {
+		return
+	}
+	// 未连接时保持pending,连接恢复后由定时重试发送
+	if !s.downlinker.IsConnected() {
+		return
+	}
+
+	status, errText := storage.DownlinkStatusSent, ""
+	err = s.downlinker.SendDownlink(backend.DownlinkPayload{
+		ApplicationID: dl.ApplicationID,
+		DevEUI:        dl.DeviceEUI,
+		Confirmed:     dl.Confirmed,
+		FPort:         dl.FPort,
+		Data:          dl.Data,
+	})
+	if err != nil {
+		if dl.Attempts+1 < maxDownlinkAttempts {
+			retryAt := time.Now().Add(downlinkRetryInterval << uint(dl.Attempts))
+			log.WithError(err).WithFields(log.Fields{
+				"device":   dl.DeviceEUI,
+				"attempts": dl.Attempts + 1,
+				"retry_at": retryAt,
+			}).Warn("send downlink error, will retry")
+			if err := storage.RetryDownlink(id, err.Error(), retryAt); err != nil {
+				log.WithError(err).WithField("id", id).Error("update downlink retry error")
+			}
+			return
+		}
+		log.WithError(err).WithField("device", dl.DeviceEUI).Error("send downlink error")
+		status, errText = storage.DownlinkStatusFailed, err.Error()
+	}
+
+	if err := storage.UpdateDownlinkStatus(id, status, errText); err != nil {
+		log.WithError(err).WithField("id", id).Error("update downlink status error")
+	}
+}
+
+// purgeUplinkLogs 定时清理过期的上行数据日志
+func (s *Server) purgeUplinkLogs() {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		n, err := storage.DeleteUplinkLogsBefore(time.Now().Add(-s.uplinkLogRetention))
+		if err != nil {
+			log.WithError(err).Error("purge uplink log error")
+		} else if n > 0 {
+			log.WithField("count", n).Info("purge uplink log")
+		}
+
+		select {
+		case <-ticker.C:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// checkOffline 定时检测超过offlineIntervals个上报间隔未上报的设备,并发布离线状态
+func (s *Server) checkOffline() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.done:
+			return
+		}
+
+		devs, err := storage.MarkOfflineDevices(s.offlineIntervals, time.Now())
+		if err != nil {
+			log.WithError(err).Error("check offline devices error")
+			continue
+		}
+		for _, dev := range devs {
+			log.WithField("device", dev.DeviceEUI).Info("device offline")
+			backend.PublishDeviceStatus(s.publisher, dev, storage.DeviceStatusOffline)
+		}
+	}
+}
+
+// Replay re-run stored uplinks through the current decoders
+func (s *Server) Replay(opts backend.ReplayOptions) ([]backend.ReplayResult, error) {
+	return backend.Replay(s.publisher, opts)
+}
+
+// NewPublisher set publish options and connect to publisher mqtt broker
+func NewPublisher(cfg config.Configuration) (paho.Client, error) {
+	err := backend.SetPublishOptions(backend.PublishOptions{
+		Mode:          cfg.Publisher.Mode,
+		TopicTemplate: cfg.Publisher.Mqtt.TopicTemplate,
+		QOS:           cfg.Publisher.Mqtt.QOS,
+		RetainFields:  cfg.Publisher.Mqtt.RetainFields,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newPublisher(cfg.Publisher.Mqtt)
+}
+
 func newPublisher(cfg mqtt.Config) (paho.Client, error) {
 
 	opts := paho.NewClientOptions()
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/maxiiot/devicebridge/backend/protocol"
+
+	"github.com/spf13/cobra"
+)
+
+var decodeFlags struct {
+	protocol string
+	format   string
+}
+
+var decodeCmd = &cobra.Command{
+	Use:   "decode [hex|base64 payload]",
+	Short: "decode device payload offline with the registered protocol decoders",
+	Long: `decode device payload offline with the registered protocol decoders.
+without payload argument, payloads are read from stdin one per line and printed as json lines.`,
+	Args: cobra.MaximumNArgs(1),
+	// decode works without configuration, postgres or mqtt
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+	RunE:             runDecode,
+}
+
+func init() {
+	decodeCmd.Flags().StringVarP(&decodeFlags.protocol, "protocol", "p", "", fmt.Sprintf("device protocol (%s)", strings.Join(protocol.Names(), "/")))
+	decodeCmd.Flags().StringVarP(&decodeFlags.format, "format", "f", "auto", "payload format (auto/hex/base64)")
+	decodeCmd.MarkFlagRequired("protocol")
+}
+
+// decodeResult output of decode command
+type decodeResult struct {
+	Payload      string                 `json:"payload"`
+	Protocol     string                 `json:"protocol"`
+	Object       interface{}            `json:"object,omitempty"`
+	Measurements []protocol.Measurement `json:"measurements,omitempty"`
+	Error        string                 `json:"error,omitempty"`
+	Field        string                 `json:"field,omitempty"` // 出错的字段
+}
+
+func runDecode(cmd *cobra.Command, args []string) error {
+	decoder, ok := protocol.Lookup(decodeFlags.protocol)
+	if !ok {
+		return fmt.Errorf("unsupported protocol %s,optional(%s)", decodeFlags.protocol, strings.Join(protocol.Names(), "/"))
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	if len(args) == 1 {
+		enc.SetIndent("", " ")
+		return enc.Encode(decodePayload(decoder, args[0]))
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		line, err := reader.ReadString('\n')
+		if payload := strings.TrimSpace(line); payload != "" {
+			if e := enc.Encode(decodePayload(decoder, payload)); e != nil {
+				return e
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func decodePayload(decoder protocol.Decoder, payload string) decodeResult {
+	result := decodeResult{Payload: payload, Protocol: decoder.Name}
+	data, err := parsePayload(payload, decodeFlags.format)
+	if err != nil {
+		result.Error = err.Error()
+		result.Field = "payload"
+		return result
+	}
+
+	decoded, err := decoder.Decode(data)
+	if err != nil {
+		result.Error = err.Error()
+		var fieldErr *protocol.FieldError
+		if errors.As(err, &fieldErr) {
+			result.Field = fieldErr.Field
+		}
+		return result
+	}
+	result.Object = decoded.Object
+	result.Measurements = decoded.Measurements
+	return result
+}
+
+// parsePayload decode hex or base64 payload, auto tries hex first
+func parsePayload(payload, format string) ([]byte, error) {
+	switch format {
+	case "hex":
+		return hex.DecodeString(payload)
+	case "base64":
+		return base64.StdEncoding.DecodeString(payload)
+	case "auto":
+		if b, err := hex.DecodeString(payload); err == nil {
+			return b, nil
+		}
+		if b, err := base64.StdEncoding.DecodeString(payload); err == nil {
+			return b, nil
+		}
+		return nil, errors.New("payload is neither hex nor base64")
+	default:
+		return nil, fmt.Errorf("unsupported payload format %s,optional(auto/hex/base64)", format)
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/maxiiot/devicebridge/backend"
+	"github.com/maxiiot/devicebridge/backend/server"
+	"github.com/maxiiot/devicebridge/config"
+	"github.com/maxiiot/devicebridge/storage"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/spf13/cobra"
+)
+
+var replayFlags struct {
+	devEUI  string
+	from    string
+	to      string
+	limit   int
+	publish bool
+	rewrite bool
+	dryRun  bool
+}
+
+var replayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "replay stored uplinks through the current decoders",
+	Long: `replay stored uplinks of a device or time range through the current decoders.
+without --publish or --rewrite (or with --dry-run) the decoded output is only printed as json.`,
+	RunE: runReplay,
+}
+
+func init() {
+	replayCmd.Flags().StringVar(&replayFlags.devEUI, "dev-eui", "", "device eui")
+	replayCmd.Flags().StringVar(&replayFlags.from, "from", "", "received time from (RFC3339)")
+	replayCmd.Flags().StringVar(&replayFlags.to, "to", "", "received time to (RFC3339)")
+	replayCmd.Flags().IntVar(&replayFlags.limit, "limit", 1000, fmt.Sprintf("max uplinks to replay (<=%d)", backend.MaxReplayLimit))
+	replayCmd.Flags().BoolVar(&replayFlags.publish, "publish", false, "republish decoded uplinks to mqtt")
+	replayCmd.Flags().BoolVar(&replayFlags.rewrite, "rewrite", false, "rewrite stored measurements and decode results")
+	replayCmd.Flags().BoolVar(&replayFlags.dryRun, "dry-run", false, "only print decoded output")
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	setLogLevel()
+
+	if replayFlags.limit <= 0 || replayFlags.limit > backend.MaxReplayLimit {
+		return fmt.Errorf("--limit must be >0 and <=%d", backend.MaxReplayLimit)
+	}
+
+	opts := backend.ReplayOptions{
+		Limit:   replayFlags.limit,
+		Publish: replayFlags.publish && !replayFlags.dryRun,
+		Rewrite: replayFlags.rewrite && !replayFlags.dryRun,
+	}
+	if replayFlags.devEUI != "" {
+		var devEUI storage.EUI64
+		if err := devEUI.UnmarshalText([]byte(replayFlags.devEUI)); err != nil {
+			return err
+		}
+		opts.DevEUI = &devEUI
+	}
+	var err error
+	if opts.From, err = parseFlagTime(replayFlags.from); err != nil {
+		return err
+	}
+	if opts.To, err = parseFlagTime(replayFlags.to); err != nil {
+		return err
+	}
+
+	if err := connectPostgres(); err != nil {
+		return err
+	}
+
+	var conn paho.Client
+	if opts.Publish {
+		conn, err = server.NewPublisher(config.Cfg)
+		if err != nil {
+			return err
+		}
+		defer conn.Disconnect(250)
+	}
+
+	results, err := backend.Replay(conn, opts)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", " ")
+	if err := enc.Encode(results); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "replayed %d uplinks\n", len(results))
+	return nil
+}
+
+// parseFlagTime parse RFC3339 flag, returns nil when empty
+func parseFlagTime(v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, fmt.Errorf("time %s must be RFC3339: %s", v, err)
+	}
+	return &t, nil
+}
@@ -16,7 +16,10 @@ var version string
 var rootCmd = &cobra.Command{
 	Use:   "vbase-bridge",
 	Short: "run vbase-bridge server",
-	RunE:  run,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		initConfig()
+	},
+	RunE: run,
 }
 
 // Execute start server
@@ -28,11 +31,12 @@ func Execute(v string) {
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
 	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "path to configuration file (optional)")
 
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(replayCmd)
+	rootCmd.AddCommand(decodeCmd)
 }
 
 func initConfig() {
@@ -23,16 +23,30 @@ type Configuration struct {
 	} `mapstructure:"loraserver" json:"loraserver"`
 
 	LoraBackend struct {
-		Mqtt     mqtt.Config `mapstructure:"mqtt" json:"mqtt"`
-		HTTPPort int         `mapstructure:"http_port" json:"http_port"`
+		Mqtt        mqtt.Config `mapstructure:"mqtt" json:"mqtt"`
+		HTTPPort    int         `mapstructure:"http_port" json:"http_port"`
+		DedupWindow int         `mapstructure:"dedup_window" json:"dedup_window"`
 	} `mapstructure:"lora_backend" json:"lora_backend"`
 
+	Downlink struct {
+		Mode string `mapstructure:"mode" json:"mode"`
+	} `mapstructure:"downlink" json:"downlink"`
+
 	Postgres struct {
 		AutoMigrate bool   `mapstructure:"auto_migrate" json:"auto_migrate"`
 		DSN         string `mapstructure:"dsn" json:"dsn"`
 	} `mapstructure:"postgres" json:"postgres"`
 
+	UplinkLog struct {
+		RetentionDays int `mapstructure:"retention_days" json:"retention_days"`
+	} `mapstructure:"uplink_log" json:"uplink_log"`
+
+	Liveness struct {
+		OfflineIntervals int `mapstructure:"offline_intervals" json:"offline_intervals"`
+	} `mapstructure:"liveness" json:"liveness"`
+
 	Publisher struct {
+		Mode string      `mapstructure:"mode" json:"mode"`
 		Mqtt mqtt.Config `mapstructure:"mqtt" json:"mqtt"`
 	} `mapstructure:"publisher" json:"publisher"`
 }
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maxiiot/devicebridge/storage"
+)
+
+// @summary 设备报警列表
+// @description 设备报警记录,默认返回未清除(open/acknowledged)的报警
+// @tags alarm
+// @accept json
+// @produce json
+// @param dev_eui path string true "device eui"
+// @param status query string false "status (open/acknowledged/cleared)"
+// @param limit query int false "limit, default 100, max 1000"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /device/{dev_eui}/alarms [get]
+func ListAlarm(c *gin.Context) {
+	var devEUI storage.EUI64
+	if err := devEUI.UnmarshalText([]byte(c.Param("dev_eui"))); err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+
+	filter := storage.AlarmFilter{Status: c.Query("status")}
+	switch filter.Status {
+	case "", storage.AlarmStatusOpen, storage.AlarmStatusAcknowledged, storage.AlarmStatusCleared:
+	default:
+		Response(c, http.StatusBadRequest, 1, "status must be open, acknowledged or cleared", nil)
+		return
+	}
+
+	var err error
+	filter.Limit, err = strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || filter.Limit <= 0 || filter.Limit > maxQueryLimit {
+		Response(c, http.StatusBadRequest, 1, "limit must be >0 and <=1000", nil)
+		return
+	}
+
+	alarms, err := storage.GetAlarms(devEUI, filter)
+	if err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", alarms)
+}
+
+// @summary 确认报警
+// @description 确认open状态的报警,确认后重复上报只累计次数,正常帧上报后自动清除
+// @tags alarm
+// @accept json
+// @produce json
+// @param id path int true "alarm id"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /alarms/{id}/ack [post]
+func AckAlarm(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		Response(c, http.StatusBadRequest, 1, "id must be integer", nil)
+		return
+	}
+
+	alarm, err := storage.AckAlarm(id, c.GetString("username"))
+	if err == sql.ErrNoRows {
+		alarm, err = storage.GetAlarm(id)
+		if err == sql.ErrNoRows {
+			Response(c, http.StatusNotFound, 1, "alarm not found", nil)
+			return
+		}
+		if err == nil {
+			Response(c, http.StatusConflict, 1, "alarm is "+alarm.Status, nil)
+			return
+		}
+	}
+	if err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", alarm)
+}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maxiiot/devicebridge/storage"
+)
+
+// @summary 设备报警事件
+// @description 解析出的设备报警事件,按报警时间倒序
+// @tags device
+// @accept json
+// @produce json
+// @param dev_eui path string true "device eui"
+// @param name query string false "alarm name (e.g.: temperature_high)"
+// @param from query string false "alarm time from (RFC3339)"
+// @param to query string false "alarm time to (RFC3339)"
+// @param limit query int false "limit, default 100, max 1000"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /device/{dev_eui}/alarm-events [get]
+func ListAlarmEvent(c *gin.Context) {
+	var devEUI storage.EUI64
+	if err := devEUI.UnmarshalText([]byte(c.Param("dev_eui"))); err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+
+	filter := storage.AlarmEventFilter{
+		Name: c.Query("name"),
+	}
+
+	from, err := parseTimeQuery(c, "from")
+	if err != nil {
+		Response(c, http.StatusBadRequest, 1, "from must be RFC3339 time", nil)
+		return
+	}
+	filter.From = from
+
+	to, err := parseTimeQuery(c, "to")
+	if err != nil {
+		Response(c, http.StatusBadRequest, 1, "to must be RFC3339 time", nil)
+		return
+	}
+	filter.To = to
+
+	filter.Limit, err = strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || filter.Limit <= 0 || filter.Limit > maxQueryLimit {
+		Response(c, http.StatusBadRequest, 1, "limit must be >0 and <=1000", nil)
+		return
+	}
+
+	events, err := storage.GetAlarmEvents(devEUI, filter)
+	if err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", events)
+}
@@ -4,6 +4,9 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// maxQueryLimit max rows of list query
+const maxQueryLimit = 1000
+
 // ResponseData vbase response
 type ResponseData struct {
 	Status  int         `json:"status"`  // 状态:。0 : 响应成功，1：响应失败
@@ -1,11 +1,15 @@
 package controllers
 
 import (
-	"errors"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"github.com/maxiiot/devicebridge/backend/protocol"
 	"github.com/maxiiot/devicebridge/backend/server"
 	"github.com/maxiiot/devicebridge/storage"
 
@@ -14,15 +18,43 @@ import (
 
 // Device for request device.
 type Device struct {
-	DeviceEUI    string `json:"device_eui" binding:"required"`
-	ProtocolType string `json:"protocol_type" example:"optional(humiture/smoke/digital)"`
+	DeviceEUI      string             `json:"device_eui" binding:"required"`
+	ProtocolType   string             `json:"protocol_type" example:"smoke"` // 设备协议,可选值见GET /protocol
+	Name           string             `json:"name" example:"1F smoke detector"`
+	Description    string             `json:"description"`
+	ApplicationID  int64              `json:"application_id" example:"1"` // lora应用ID
+	Tags           []string           `json:"tags"`
+	Location       *storage.GPSPoint  `json:"location"` // 安装位置
+	Attributes     storage.Attributes `json:"attributes"`
+	ReportInterval int                `json:"report_interval" example:"3600"` // 预期上报间隔(秒),0表示不检测离线
+}
+
+// supportedProtocols returns registered decoders and the default protocol
+func supportedProtocols() []string {
+	return append(protocol.Names(), storage.ProtocolDefault)
 }
 
 func (dev *Device) validate() error {
+	if dev.ReportInterval < 0 {
+		return fmt.Errorf("report_interval must be >=0")
+	}
+	if len(dev.Name) > 100 {
+		return fmt.Errorf("name length must <=100")
+	}
+	if dev.Location != nil && (math.Abs(dev.Location.Latitude) > 90 || math.Abs(dev.Location.Longitude) > 180) {
+		return fmt.Errorf("location out of range")
+	}
+	for _, tag := range dev.Tags {
+		if tag == "" {
+			return fmt.Errorf("tag must not be empty")
+		}
+	}
 	dev.ProtocolType = strings.ToLower(dev.ProtocolType)
-	if dev.ProtocolType != storage.ProtocolHumiture && dev.ProtocolType != storage.ProtocolSmoke &&
-		dev.ProtocolType != storage.ProtocolDefault {
-		return errors.New("Unsupported device protocol.optional(humiture/smoke/digital)")
+	if dev.ProtocolType == storage.ProtocolDefault {
+		return nil
+	}
+	if _, ok := protocol.Lookup(dev.ProtocolType); !ok {
+		return fmt.Errorf("Unsupported device protocol.optional(%s)", strings.Join(supportedProtocols(), "/"))
 	}
 	return nil
 }
@@ -36,9 +68,56 @@ func (dev Device) toStorageDevice() (storage.Device, error) {
 	}
 	sDev.DeviceEUI = devEUI
 	sDev.ProtocolType = dev.ProtocolType
+	sDev.Name = dev.Name
+	sDev.Description = dev.Description
+	sDev.ApplicationID = dev.ApplicationID
+	sDev.Tags = dev.Tags
+	sDev.Location = dev.Location
+	sDev.Attributes = dev.Attributes
+	sDev.ReportInterval = dev.ReportInterval
 	return sDev, nil
 }
 
+// fromStorageDevice convert storage device to request device
+func fromStorageDevice(d storage.Device) Device {
+	return Device{
+		DeviceEUI:      d.DeviceEUI.String(),
+		ProtocolType:   d.ProtocolType,
+		Name:           d.Name,
+		Description:    d.Description,
+		ApplicationID:  d.ApplicationID,
+		Tags:           d.Tags,
+		Location:       d.Location,
+		Attributes:     d.Attributes,
+		ReportInterval: d.ReportInterval,
+	}
+}
+
+// mergeDeviceUpdate apply fields present in update body to stored device,
+// fields not in body keep stored values
+func mergeDeviceUpdate(stored storage.Device, body []byte) (Device, error) {
+	dev := fromStorageDevice(stored)
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return dev, err
+	}
+	// json decodes into existing slice, map and pointer, reset them so they are replaced
+	// instead of merged or written into stored device
+	if _, ok := fields["tags"]; ok {
+		dev.Tags = nil
+	}
+	if _, ok := fields["attributes"]; ok {
+		dev.Attributes = nil
+	}
+	if _, ok := fields["location"]; ok {
+		dev.Location = nil
+	}
+
+	err := json.Unmarshal(body, &dev)
+	return dev, err
+}
+
 // @summary 新增设备
 // @description 新增设备
 // @tags device
@@ -80,12 +159,26 @@ func CreateDevice(c *gin.Context) {
 }
 
 // @summary 设备列表
-// @description 设备列表
+// @description 设备列表,按排序字段和EUI稳定排序,返回next_cursor用于游标分页
 // @tags device
 // @accept json
 // @produce json
 // @param page query int true "page"
 // @param perpage query int true "perpage"
+// @param name query string false "name contains"
+// @param application_id query int false "lora application id"
+// @param tag query string false "has tag"
+// @param attr query []string false "has attribute (key:value), repeatable"
+// @param bbox query string false "location within box (min_lat,min_lng,max_lat,max_lng)"
+// @param group_id query int false "group id, devices of descendant groups included"
+// @param protocol query string false "protocol type"
+// @param eui_prefix query string false "device eui hex prefix"
+// @param status query string false "status (online/offline/unknown)"
+// @param created_from query string false "created time from (RFC3339)"
+// @param created_to query string false "created time to (RFC3339)"
+// @param sort query string false "sort by created/last_seen/eui, default created"
+// @param order query string false "asc/desc, default asc"
+// @param cursor query string false "next_cursor of previous page, page is ignored when present"
 // @success 200 {object} controllers.ResponseData
 // @failure 500 {object} controllers.ResponseData
 // @security ApiKeyAuth
@@ -106,27 +199,156 @@ func ListDevice(c *gin.Context) {
 		return
 	}
 
+	filter, err := parseDeviceFilter(c)
+	if err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+
+	order, err := parseDeviceOrder(c)
+	if err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+
 	limit := perpage
 	offset := perpage * (page - 1)
 
-	devs, err := storage.GetDevices(limit, offset)
+	devs, err := storage.GetDevices(filter, order, limit, offset)
 	if err != nil {
 		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
 		return
 	}
 
-	count, err := storage.GetDevicesCount()
+	var nextCursor string
+	if len(devs) == limit {
+		nextCursor = order.Cursor(devs[len(devs)-1]).String()
+	}
+
+	count, err := storage.GetDevicesCount(filter)
 	if err != nil {
 		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
 		return
 	}
 
 	Response(c, http.StatusOK, 0, "success", gin.H{
-		"total":   count,
-		"devices": devs,
+		"total":       count,
+		"devices":     devs,
+		"next_cursor": nextCursor,
 	})
 }
 
+// parseDeviceOrder parse device list sort, order and cursor query
+func parseDeviceOrder(c *gin.Context) (storage.DeviceOrder, error) {
+	var order storage.DeviceOrder
+	switch c.DefaultQuery("sort", "created") {
+	case "created":
+		order.By = storage.DeviceOrderCreated
+	case "last_seen":
+		order.By = storage.DeviceOrderLastSeen
+	case "eui":
+		order.By = storage.DeviceOrderEUI
+	default:
+		return order, fmt.Errorf("sort must be created, last_seen or eui")
+	}
+
+	switch c.DefaultQuery("order", "asc") {
+	case "asc":
+	case "desc":
+		order.Desc = true
+	default:
+		return order, fmt.Errorf("order must be asc or desc")
+	}
+
+	if v := c.Query("cursor"); v != "" {
+		cursor, err := storage.ParseDeviceCursor(v)
+		if err != nil {
+			return order, err
+		}
+		if (cursor.Value == nil) != (order.By == storage.DeviceOrderEUI) {
+			return order, fmt.Errorf("cursor does not match sort")
+		}
+		order.After = &cursor
+	}
+	return order, nil
+}
+
+// parseDeviceFilter parse device list filter query
+func parseDeviceFilter(c *gin.Context) (storage.DeviceFilter, error) {
+	filter := storage.DeviceFilter{
+		Name:         c.Query("name"),
+		Tag:          c.Query("tag"),
+		ProtocolType: strings.ToLower(c.Query("protocol")),
+		EUIPrefix:    strings.ToLower(c.Query("eui_prefix")),
+		Status:       c.Query("status"),
+	}
+
+	if len(filter.EUIPrefix) > 16 || strings.Trim(filter.EUIPrefix, "0123456789abcdef") != "" {
+		return filter, fmt.Errorf("eui_prefix must be hex and length <=16")
+	}
+
+	switch filter.Status {
+	case "", storage.DeviceStatusOnline, storage.DeviceStatusOffline, storage.DeviceStatusUnknown:
+	default:
+		return filter, fmt.Errorf("status must be online, offline or unknown")
+	}
+
+	var err error
+	if filter.CreatedFrom, err = parseTimeQuery(c, "created_from"); err != nil {
+		return filter, fmt.Errorf("created_from must be RFC3339 time")
+	}
+	if filter.CreatedTo, err = parseTimeQuery(c, "created_to"); err != nil {
+		return filter, fmt.Errorf("created_to must be RFC3339 time")
+	}
+
+	if v := c.Query("application_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("application_id must be integer")
+		}
+		filter.ApplicationID = id
+	}
+
+	if v := c.Query("group_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("group_id must be integer")
+		}
+		filter.GroupID = id
+	}
+
+	for _, attr := range c.QueryArray("attr") {
+		kv := strings.SplitN(attr, ":", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return filter, fmt.Errorf("attr must be key:value")
+		}
+		if filter.Attributes == nil {
+			filter.Attributes = make(storage.Attributes)
+		}
+		filter.Attributes[kv[0]] = kv[1]
+	}
+
+	if v := c.Query("bbox"); v != "" {
+		parts := strings.Split(v, ",")
+		if len(parts) != 4 {
+			return filter, fmt.Errorf("bbox must be min_lat,min_lng,max_lat,max_lng")
+		}
+		var f [4]float64
+		for i, p := range parts {
+			n, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+			if err != nil {
+				return filter, fmt.Errorf("bbox must be min_lat,min_lng,max_lat,max_lng")
+			}
+			f[i] = n
+		}
+		filter.Within = &[2]storage.GPSPoint{
+			{Latitude: f[0], Longitude: f[1]},
+			{Latitude: f[2], Longitude: f[3]},
+		}
+	}
+	return filter, nil
+}
+
 // @summary 设备明细
 // @description  设备明细
 // @tags device
@@ -154,7 +376,7 @@ func GetDevice(c *gin.Context) {
 }
 
 // @summary 修改设备
-// @description 修改设备
+// @description 修改设备,只更新请求中包含的字段
 // @tags device
 // @accept json
 // @produce json
@@ -164,8 +386,35 @@ func GetDevice(c *gin.Context) {
 // @security ApiKeyAuth
 // @router /device [put]
 func UpdateDevice(c *gin.Context) {
-	var dev Device
-	err := c.ShouldBind(&dev)
+	body, err := c.GetRawData()
+	if err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+
+	var req struct {
+		DeviceEUI string `json:"device_eui"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+	if req.DeviceEUI == "" {
+		Response(c, http.StatusBadRequest, 1, "device_eui is required", nil)
+		return
+	}
+
+	stored, err := storage.GetDeviceByEUI(req.DeviceEUI)
+	if err == sql.ErrNoRows {
+		Response(c, http.StatusNotFound, 1, "device not found", nil)
+		return
+	}
+	if err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+
+	dev, err := mergeDeviceUpdate(stored, body)
 	if err != nil {
 		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
 		return
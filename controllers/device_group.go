@@ -0,0 +1,396 @@
+package controllers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maxiiot/devicebridge/storage"
+)
+
+// DeviceGroup for request device group.
+type DeviceGroup struct {
+	ParentID    *int64 `json:"parent_id" example:"1"` // 上级分组,空表示顶级分组
+	Name        string `json:"name" binding:"required" example:"building-1"`
+	Description string `json:"description"`
+}
+
+// DeviceGroupMembers for request device group members.
+type DeviceGroupMembers struct {
+	DeviceEUIs []string `json:"device_euis" binding:"required"`
+}
+
+func (g DeviceGroup) validate() error {
+	if len(g.Name) > 100 {
+		return fmt.Errorf("name length must <=100")
+	}
+	// name is part of publish topic
+	if strings.ContainsAny(g.Name, "/+#") {
+		return fmt.Errorf("name must not contain / + #")
+	}
+	return nil
+}
+
+// groupIDParam parse group id path param
+func groupIDParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		Response(c, http.StatusBadRequest, 1, "id must be integer", nil)
+		return 0, false
+	}
+	return id, true
+}
+
+// responseGroupError response storage error of device group
+func responseGroupError(c *gin.Context, err error) {
+	switch err {
+	case sql.ErrNoRows:
+		Response(c, http.StatusNotFound, 1, "group not found", nil)
+	case storage.ErrGroupExists, storage.ErrGroupNotEmpty, storage.ErrGroupCycle:
+		Response(c, http.StatusConflict, 1, err.Error(), nil)
+	default:
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+	}
+}
+
+// checkParentGroup response 400 if parent group not exists
+func checkParentGroup(c *gin.Context, parentID *int64) bool {
+	if parentID == nil {
+		return true
+	}
+	if _, err := storage.GetDeviceGroup(*parentID); err != nil {
+		if err == sql.ErrNoRows {
+			Response(c, http.StatusBadRequest, 1, "parent group not found", nil)
+		} else {
+			Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		}
+		return false
+	}
+	return true
+}
+
+// @summary 设备分组列表
+// @description 下级分组列表,不传parent_id时返回顶级分组
+// @tags group
+// @accept json
+// @produce json
+// @param parent_id query int false "parent group id"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /device-groups [get]
+func ListDeviceGroup(c *gin.Context) {
+	var parentID *int64
+	if v := c.Query("parent_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			Response(c, http.StatusBadRequest, 1, "parent_id must be integer", nil)
+			return
+		}
+		parentID = &id
+	}
+
+	groups, err := storage.GetDeviceGroups(parentID)
+	if err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", groups)
+}
+
+// @summary 新增设备分组
+// @description 新增设备分组,可嵌套,如site/building/floor
+// @tags group
+// @accept json
+// @produce json
+// @param group body controllers.DeviceGroup true "create group info"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /device-groups [post]
+func CreateDeviceGroup(c *gin.Context) {
+	var g DeviceGroup
+	if err := c.ShouldBind(&g); err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+	if err := g.validate(); err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+	if !checkParentGroup(c, g.ParentID) {
+		return
+	}
+
+	group, err := storage.CreateDeviceGroup(storage.DeviceGroup{
+		ParentID:    g.ParentID,
+		Name:        g.Name,
+		Description: g.Description,
+	})
+	if err != nil {
+		responseGroupError(c, err)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", group)
+}
+
+// @summary 设备分组明细
+// @description 设备分组明细
+// @tags group
+// @accept json
+// @produce json
+// @param id path int true "group id"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /device-groups/{id} [get]
+func GetDeviceGroup(c *gin.Context) {
+	id, ok := groupIDParam(c)
+	if !ok {
+		return
+	}
+
+	group, err := storage.GetDeviceGroup(id)
+	if err != nil {
+		responseGroupError(c, err)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", group)
+}
+
+// @summary 修改设备分组
+// @description 修改设备分组,修改parent_id可移动分组,不能移动到自身或下级分组
+// @tags group
+// @accept json
+// @produce json
+// @param id path int true "group id"
+// @param group body controllers.DeviceGroup true "update group info"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /device-groups/{id} [put]
+func UpdateDeviceGroup(c *gin.Context) {
+	id, ok := groupIDParam(c)
+	if !ok {
+		return
+	}
+
+	var g DeviceGroup
+	if err := c.ShouldBind(&g); err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+	if err := g.validate(); err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+	if !checkParentGroup(c, g.ParentID) {
+		return
+	}
+
+	group, err := storage.UpdateDeviceGroup(storage.DeviceGroup{
+		ID:          id,
+		ParentID:    g.ParentID,
+		Name:        g.Name,
+		Description: g.Description,
+	})
+	if err != nil {
+		responseGroupError(c, err)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", group)
+}
+
+// @summary 删除设备分组
+// @description 删除没有下级分组的设备分组,分组内设备变为未分组
+// @tags group
+// @accept json
+// @produce json
+// @param id path int true "group id"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /device-groups/{id} [delete]
+func DeleteDeviceGroup(c *gin.Context) {
+	id, ok := groupIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := storage.DeleteDeviceGroup(id); err != nil {
+		responseGroupError(c, err)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", nil)
+}
+
+// @summary 分组设备列表
+// @description 分组及其下级分组的设备列表
+// @tags group
+// @accept json
+// @produce json
+// @param id path int true "group id"
+// @param page query int true "page"
+// @param perpage query int true "perpage"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /device-groups/{id}/devices [get]
+func ListDeviceGroupDevice(c *gin.Context) {
+	id, ok := groupIDParam(c)
+	if !ok {
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		Response(c, http.StatusBadRequest, 1, "page must be >=1", nil)
+		return
+	}
+	perpage, err := strconv.Atoi(c.DefaultQuery("perpage", "10"))
+	if err != nil || perpage <= 0 {
+		Response(c, http.StatusBadRequest, 1, "perpage must be >0", nil)
+		return
+	}
+
+	if _, err := storage.GetDeviceGroup(id); err != nil {
+		responseGroupError(c, err)
+		return
+	}
+
+	filter := storage.DeviceFilter{GroupID: id}
+	devs, err := storage.GetDevices(filter, storage.DeviceOrder{By: storage.DeviceOrderEUI}, perpage, perpage*(page-1))
+	if err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
+	count, err := storage.GetDevicesCount(filter)
+	if err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", gin.H{
+		"total":   count,
+		"devices": devs,
+	})
+}
+
+// @summary 添加分组设备
+// @description 将设备移动到分组,设备原有分组会被替换
+// @tags group
+// @accept json
+// @produce json
+// @param id path int true "group id"
+// @param members body controllers.DeviceGroupMembers true "device euis"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /device-groups/{id}/devices [post]
+func AddDeviceGroupMember(c *gin.Context) {
+	id, ok := groupIDParam(c)
+	if !ok {
+		return
+	}
+
+	var m DeviceGroupMembers
+	if err := c.ShouldBind(&m); err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+	devEUIs := make([]storage.EUI64, len(m.DeviceEUIs))
+	for i, eui := range m.DeviceEUIs {
+		if err := devEUIs[i].UnmarshalText([]byte(eui)); err != nil {
+			Response(c, http.StatusBadRequest, 1, fmt.Sprintf("device_euis[%d]: %s", i, err), nil)
+			return
+		}
+	}
+
+	if _, err := storage.GetDeviceGroup(id); err != nil {
+		responseGroupError(c, err)
+		return
+	}
+
+	n, err := storage.AddDeviceGroupMembers(id, devEUIs)
+	if err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", gin.H{"updated": n})
+}
+
+// @summary 移除分组设备
+// @description 将设备从分组移除
+// @tags group
+// @accept json
+// @produce json
+// @param id path int true "group id"
+// @param dev_eui path string true "device eui"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /device-groups/{id}/devices/{dev_eui} [delete]
+func RemoveDeviceGroupMember(c *gin.Context) {
+	id, ok := groupIDParam(c)
+	if !ok {
+		return
+	}
+
+	var devEUI storage.EUI64
+	if err := devEUI.UnmarshalText([]byte(c.Param("dev_eui"))); err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+
+	n, err := storage.RemoveDeviceGroupMembers(id, []storage.EUI64{devEUI})
+	if err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+	if n == 0 {
+		Response(c, http.StatusNotFound, 1, "device not in group", nil)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", nil)
+}
+
+// @summary 设备分组统计
+// @description 分组及其下级分组的设备数、在线/离线数和未确认报警数
+// @tags group
+// @accept json
+// @produce json
+// @param id path int true "group id"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /device-groups/{id}/stats [get]
+func GetDeviceGroupStats(c *gin.Context) {
+	id, ok := groupIDParam(c)
+	if !ok {
+		return
+	}
+
+	if _, err := storage.GetDeviceGroup(id); err != nil {
+		responseGroupError(c, err)
+		return
+	}
+
+	stats, err := storage.GetDeviceGroupStats(id)
+	if err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", stats)
+}
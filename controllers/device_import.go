@@ -0,0 +1,456 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maxiiot/devicebridge/backend/server"
+	"github.com/maxiiot/devicebridge/storage"
+)
+
+const (
+	// ImportModeTransactional all rows are created or none
+	ImportModeTransactional = "transactional"
+	// ImportModeBestEffort valid rows are created, invalid rows are skipped
+	ImportModeBestEffort = "best_effort"
+
+	formatCSV  = "csv"
+	formatJSON = "json"
+
+	// maxImportRows max rows of one import
+	maxImportRows = 10000
+)
+
+// deviceCSVHeader csv columns of device import/export
+// tags和attributes为json,如["1f","lobby"]和{"room":"101"}
+var deviceCSVHeader = []string{
+	"device_eui",
+	"protocol_type",
+	"name",
+	"description",
+	"application_id",
+	"tags",
+	"latitude",
+	"longitude",
+	"attributes",
+	"report_interval",
+}
+
+// ImportResult import result of one row
+type ImportResult struct {
+	Row       int    `json:"row"` // 数据行号,从1开始,不含csv表头
+	DeviceEUI string `json:"device_eui"`
+	Created   bool   `json:"created"`
+	Error     string `json:"error,omitempty"`
+}
+
+// @summary 批量导入设备
+// @description 批量导入设备,支持csv(首行为表头)或json数组,可直接提交body或以multipart的file字段上传。
+// @description transactional模式下任一行失败则全部不导入,best_effort模式下跳过失败的行
+// @tags device
+// @accept json,text/csv,multipart/form-data
+// @produce json
+// @param format query string false "csv/json, default by Content-Type or file extension"
+// @param mode query string false "transactional/best_effort, default transactional"
+// @param file formData file false "import file"
+// @success 200 {object} controllers.ResponseData
+// @failure 400 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /devices/import [post]
+func ImportDevice(c *gin.Context) {
+	mode := c.DefaultQuery("mode", ImportModeTransactional)
+	if mode != ImportModeTransactional && mode != ImportModeBestEffort {
+		Response(c, http.StatusBadRequest, 1, "mode must be transactional or best_effort", nil)
+		return
+	}
+
+	body, format, err := readImportBody(c)
+	if err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+
+	var devs []Device
+	var rowErrs []string
+	switch format {
+	case formatCSV:
+		devs, rowErrs, err = decodeDeviceCSV(bytes.NewReader(body))
+	case formatJSON:
+		err = json.Unmarshal(body, &devs)
+	default:
+		err = fmt.Errorf("format must be csv or json")
+	}
+	if err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+	if len(devs) == 0 {
+		Response(c, http.StatusBadRequest, 1, "no device to import", nil)
+		return
+	}
+	if len(devs) > maxImportRows {
+		Response(c, http.StatusBadRequest, 1, fmt.Sprintf("import rows must <=%d", maxImportRows), nil)
+		return
+	}
+
+	results, sDevs := validateImport(devs, rowErrs)
+
+	invalid := false
+	for _, r := range results {
+		if r.Error != "" {
+			invalid = true
+			break
+		}
+	}
+
+	notice := make(map[string]bool)
+	if mode == ImportModeTransactional {
+		if invalid {
+			Response(c, http.StatusBadRequest, 1, "import aborted, no device created", importSummary(results))
+			return
+		}
+
+		idx, err := storage.CreateDevices(sDevs)
+		if err != nil {
+			if idx < 0 {
+				Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+				return
+			}
+			results[idx].Error = err.Error()
+			Response(c, http.StatusBadRequest, 1, "import aborted, no device created", importSummary(results))
+			return
+		}
+		for i := range results {
+			results[i].Created = true
+			notice[results[i].DeviceEUI] = true
+		}
+	} else {
+		j := 0
+		for i := range results {
+			if results[i].Error != "" {
+				continue
+			}
+			if err := storage.CreateDevice(sDevs[j]); err != nil {
+				results[i].Error = err.Error()
+			} else {
+				results[i].Created = true
+				notice[results[i].DeviceEUI] = true
+			}
+			j++
+		}
+	}
+
+	if len(notice) > 0 {
+		server.Serv.OnDeviceChange(notice)
+	}
+
+	Response(c, http.StatusOK, 0, "success", importSummary(results))
+}
+
+// readImportBody read import content from multipart file or request body
+func readImportBody(c *gin.Context) ([]byte, string, error) {
+	format := strings.ToLower(c.Query("format"))
+
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			return nil, "", fmt.Errorf("please upload import file")
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return nil, "", err
+		}
+		defer f.Close()
+
+		if format == "" {
+			switch {
+			case strings.HasSuffix(strings.ToLower(fh.Filename), ".csv"):
+				format = formatCSV
+			case strings.HasSuffix(strings.ToLower(fh.Filename), ".json"):
+				format = formatJSON
+			}
+		}
+		b, err := ioutil.ReadAll(f)
+		return b, format, err
+	}
+
+	if format == "" {
+		if c.ContentType() == "text/csv" {
+			format = formatCSV
+		} else {
+			format = formatJSON
+		}
+	}
+	b, err := ioutil.ReadAll(c.Request.Body)
+	return b, format, err
+}
+
+// validateImport validate import devices, returns result of each row and valid devices,
+// rowErrs are parse errors of csv rows, nil for json
+func validateImport(devs []Device, rowErrs []string) ([]ImportResult, []storage.Device) {
+	results := make([]ImportResult, len(devs))
+	sDevs := make([]storage.Device, 0, len(devs))
+	seen := make(map[string]int, len(devs))
+
+	for i := range devs {
+		dev := &devs[i]
+		dev.DeviceEUI = strings.ToLower(strings.TrimSpace(dev.DeviceEUI))
+		results[i] = ImportResult{Row: i + 1, DeviceEUI: dev.DeviceEUI}
+
+		if rowErrs != nil && rowErrs[i] != "" {
+			results[i].Error = rowErrs[i]
+			continue
+		}
+		if dev.DeviceEUI == "" {
+			results[i].Error = "device_eui is required"
+			continue
+		}
+		if row, ok := seen[dev.DeviceEUI]; ok {
+			results[i].Error = fmt.Sprintf("duplicate device_eui of row %d", row)
+			continue
+		}
+		seen[dev.DeviceEUI] = i + 1
+
+		if err := dev.validate(); err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		sDev, err := dev.toStorageDevice()
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		sDevs = append(sDevs, sDev)
+	}
+	return results, sDevs
+}
+
+func importSummary(results []ImportResult) gin.H {
+	created := 0
+	failed := 0
+	for _, r := range results {
+		if r.Created {
+			created++
+		}
+		if r.Error != "" {
+			failed++
+		}
+	}
+	return gin.H{
+		"total":   len(results),
+		"created": created,
+		"failed":  failed,
+		"rows":    results,
+	}
+}
+
+// @summary 导出设备
+// @description 导出设备,格式与批量导入一致,支持与设备列表相同的过滤条件
+// @tags device
+// @produce json,text/csv
+// @param format query string false "csv/json, default csv"
+// @param name query string false "name contains"
+// @param application_id query int false "lora application id"
+// @param tag query string false "has tag"
+// @param attr query []string false "has attribute (key:value), repeatable"
+// @param bbox query string false "location within box (min_lat,min_lng,max_lat,max_lng)"
+// @param group_id query int false "group id, devices of descendant groups included"
+// @param protocol query string false "protocol type"
+// @param eui_prefix query string false "device eui hex prefix"
+// @param status query string false "status (online/offline/unknown)"
+// @param created_from query string false "created time from (RFC3339)"
+// @param created_to query string false "created time to (RFC3339)"
+// @success 200 {array} controllers.Device
+// @failure 400 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /devices/export [get]
+func ExportDevice(c *gin.Context) {
+	format := strings.ToLower(c.DefaultQuery("format", formatCSV))
+	if format != formatCSV && format != formatJSON {
+		Response(c, http.StatusBadRequest, 1, "format must be csv or json", nil)
+		return
+	}
+
+	filter, err := parseDeviceFilter(c)
+	if err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+
+	sDevs, err := storage.GetAllDevices(filter)
+	if err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
+	devs := make([]Device, 0, len(sDevs))
+	for _, d := range sDevs {
+		devs = append(devs, fromStorageDevice(d))
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=devices."+format)
+	if format == formatJSON {
+		c.JSON(http.StatusOK, devs)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := encodeDeviceCSV(&buf, devs); err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// decodeDeviceCSV decode devices from csv, first line is header.
+// invalid cells are returned as error of the row, only header or csv syntax error fails the whole file
+func decodeDeviceCSV(r io.Reader) ([]Device, []string, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	cols := make(map[string]int, len(header))
+	for i, h := range header {
+		cols[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+	}
+	if _, ok := cols["device_eui"]; !ok {
+		return nil, nil, fmt.Errorf("csv header must contain device_eui")
+	}
+
+	var devs []Device
+	var rowErrs []string
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		dev, err := decodeDeviceRecord(cols, record)
+		devs = append(devs, dev)
+		if err != nil {
+			rowErrs = append(rowErrs, err.Error())
+		} else {
+			rowErrs = append(rowErrs, "")
+		}
+	}
+	return devs, rowErrs, nil
+}
+
+// decodeDeviceRecord decode device of one csv record, cols is column index by header name
+func decodeDeviceRecord(cols map[string]int, record []string) (Device, error) {
+	get := func(name string) string {
+		if i, ok := cols[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var err error
+	dev := Device{
+		DeviceEUI:    get("device_eui"),
+		ProtocolType: get("protocol_type"),
+		Name:         get("name"),
+		Description:  get("description"),
+	}
+	if v := get("application_id"); v != "" {
+		if dev.ApplicationID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return dev, fmt.Errorf("application_id must be integer")
+		}
+	}
+	if v := get("report_interval"); v != "" {
+		if dev.ReportInterval, err = strconv.Atoi(v); err != nil {
+			return dev, fmt.Errorf("report_interval must be integer")
+		}
+	}
+	if v := get("tags"); v != "" {
+		if err = json.Unmarshal([]byte(v), &dev.Tags); err != nil {
+			return dev, fmt.Errorf("tags must be json array of string")
+		}
+	}
+	lat, lng := get("latitude"), get("longitude")
+	if lat != "" || lng != "" {
+		var p storage.GPSPoint
+		p.Latitude, err = strconv.ParseFloat(lat, 64)
+		if err == nil {
+			p.Longitude, err = strconv.ParseFloat(lng, 64)
+		}
+		if err != nil {
+			return dev, fmt.Errorf("latitude and longitude must be number")
+		}
+		dev.Location = &p
+	}
+	if v := get("attributes"); v != "" {
+		if err = json.Unmarshal([]byte(v), &dev.Attributes); err != nil {
+			return dev, fmt.Errorf("attributes must be json object of string")
+		}
+	}
+	return dev, nil
+}
+
+// encodeDeviceCSV encode devices to csv with header
+func encodeDeviceCSV(w io.Writer, devs []Device) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(deviceCSVHeader); err != nil {
+		return err
+	}
+	for _, dev := range devs {
+		var lat, lng string
+		if dev.Location != nil {
+			lat = strconv.FormatFloat(dev.Location.Latitude, 'f', -1, 64)
+			lng = strconv.FormatFloat(dev.Location.Longitude, 'f', -1, 64)
+		}
+		tags, err := jsonCell(dev.Tags, len(dev.Tags))
+		if err != nil {
+			return err
+		}
+		attrs, err := jsonCell(dev.Attributes, len(dev.Attributes))
+		if err != nil {
+			return err
+		}
+
+		err = cw.Write([]string{
+			dev.DeviceEUI,
+			dev.ProtocolType,
+			dev.Name,
+			dev.Description,
+			strconv.FormatInt(dev.ApplicationID, 10),
+			tags,
+			lat,
+			lng,
+			attrs,
+			strconv.Itoa(dev.ReportInterval),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// jsonCell encode v as json csv cell, empty when n is 0
+func jsonCell(v interface{}, n int) (string, error) {
+	if n == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(v)
+	return string(b), err
+}
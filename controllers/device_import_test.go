@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/maxiiot/devicebridge/storage"
+)
+
+func TestDeviceCSVRoundTrip(t *testing.T) {
+	devs := []Device{
+		{
+			DeviceEUI:      "0102030405060708",
+			ProtocolType:   storage.ProtocolSmoke,
+			Name:           "smoke; 1F, \"lobby\"",
+			Description:    "line1\nline2",
+			ApplicationID:  2,
+			Tags:           []string{"a;b", "c:d", "e,f"},
+			Location:       &storage.GPSPoint{Latitude: 22.54, Longitude: 114.05},
+			Attributes:     storage.Attributes{"room:no": "1;01", "k": "v:w;x"},
+			ReportInterval: 3600,
+		},
+		{
+			DeviceEUI:    "0102030405060709",
+			ProtocolType: storage.ProtocolDefault,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := encodeDeviceCSV(&buf, devs); err != nil {
+		t.Fatal(err)
+	}
+	got, rowErrs, err := decodeDeviceCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rowErrs, []string{"", ""}) {
+		t.Errorf("unexpected row errors: %q", rowErrs)
+	}
+	if !reflect.DeepEqual(got, devs) {
+		t.Errorf("round trip:\ngot  %+v\nwant %+v", got, devs)
+	}
+}
+
+func TestImportCSVBadCell(t *testing.T) {
+	body := "device_eui,protocol_type,application_id,tags,latitude,longitude\n" +
+		"0102030405060701,smoke,1,,,\n" +
+		"0102030405060702,smoke,x,,,\n" +
+		"0102030405060703,smoke,1,not-json,,\n" +
+		"0102030405060704,smoke,1,,22.5,\n" +
+		"0102030405060705,smoke,1,\"[\"\"1f\"\"]\",22.5,114.1\n"
+	devs, rowErrs, err := decodeDeviceCSV(bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal("bad cell should not fail the whole file:", err)
+	}
+
+	// best_effort只导入有效行,无效行返回错误
+	results, sDevs := validateImport(devs, rowErrs)
+	want := []string{
+		"",
+		"application_id must be integer",
+		"tags must be json array of string",
+		"latitude and longitude must be number",
+		"",
+	}
+	if len(results) != len(want) {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	for i, r := range results {
+		if r.Row != i+1 || r.Error != want[i] {
+			t.Errorf("row %d: got %+v, want error %q", i+1, r, want[i])
+		}
+	}
+	if len(sDevs) != 2 || sDevs[0].DeviceEUI.String() != "0102030405060701" || sDevs[1].DeviceEUI.String() != "0102030405060705" {
+		t.Errorf("unexpected valid devices: %+v", sDevs)
+	}
+}
+
+func TestImportCSVBadHeader(t *testing.T) {
+	if _, _, err := decodeDeviceCSV(bytes.NewBufferString("name,protocol_type\nfoo,smoke\n")); err == nil {
+		t.Error("header without device_eui should fail")
+	}
+}
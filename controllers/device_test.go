@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/maxiiot/devicebridge/storage"
+)
+
+func TestMergeDeviceUpdate(t *testing.T) {
+	stored := storage.Device{
+		DeviceEUI:      storage.EUI64{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+		ProtocolType:   storage.ProtocolHumiture,
+		Name:           "1F humiture",
+		Description:    "lobby",
+		ApplicationID:  3,
+		Tags:           []string{"lobby", "1f"},
+		Location:       &storage.GPSPoint{Latitude: 22.5, Longitude: 114.1},
+		Attributes:     storage.Attributes{"vendor": "maxiiot", "room": "101"},
+		ReportInterval: 600,
+	}
+
+	// protocol only update keeps metadata
+	dev, err := mergeDeviceUpdate(stored, []byte(`{"device_eui":"0102030405060708","protocol_type":"smoke"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := fromStorageDevice(stored)
+	want.ProtocolType = "smoke"
+	if !reflect.DeepEqual(dev, want) {
+		t.Errorf("protocol only update: got %+v, want %+v", dev, want)
+	}
+
+	// sent attributes and location replace stored ones
+	dev, err = mergeDeviceUpdate(stored, []byte(`{"device_eui":"0102030405060708","attributes":{"room":"102"},"location":null}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dev.Attributes, storage.Attributes{"room": "102"}) {
+		t.Errorf("attributes: got %v", dev.Attributes)
+	}
+	if dev.Location != nil {
+		t.Errorf("location: got %v, want nil", dev.Location)
+	}
+	if stored.Location == nil || stored.Attributes["vendor"] != "maxiiot" {
+		t.Error("stored device modified")
+	}
+	if dev.Name != stored.Name || dev.ReportInterval != stored.ReportInterval {
+		t.Errorf("unsent fields changed: %+v", dev)
+	}
+}
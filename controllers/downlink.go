@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maxiiot/devicebridge/backend/protocol"
+	"github.com/maxiiot/devicebridge/backend/server"
+	"github.com/maxiiot/devicebridge/storage"
+)
+
+// Downlink downlink request info
+type Downlink struct {
+	Data          string            `json:"data" example:"hex payload, optional when command is set"`
+	Command       *protocol.Command `json:"command"`
+	FPort         uint8             `json:"fport" binding:"required" example:"10"`
+	Confirmed     bool              `json:"confirmed"`
+	ApplicationID int64             `json:"application_id" example:"0"`
+}
+
+// @summary 发送下行数据
+// @description 发送原始hex数据或指令到设备,application_id为0时取设备的应用ID或最近上行数据的应用
+// @tags device
+// @accept json
+// @produce json
+// @param dev_eui path string true "device eui"
+// @param downlink body controllers.Downlink true "downlink info"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /device/{dev_eui}/downlink [post]
+func CreateDownlink(c *gin.Context) {
+	var req Downlink
+	if err := c.ShouldBind(&req); err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+	if (req.Data == "") == (req.Command == nil) {
+		Response(c, http.StatusBadRequest, 1, "please provide one of data or command", nil)
+		return
+	}
+
+	dev, err := storage.GetDeviceByEUI(c.Param("dev_eui"))
+	if err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+
+	dl := storage.Downlink{
+		DeviceEUI:     dev.DeviceEUI,
+		ApplicationID: req.ApplicationID,
+		FPort:         req.FPort,
+		Confirmed:     req.Confirmed,
+	}
+	if req.Command != nil {
+		dl.Data, err = protocol.EncodeCommand(dev.ProtocolType, *req.Command)
+		if err != nil {
+			Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+			return
+		}
+		b, _ := json.Marshal(req.Command)
+		dl.Command = string(b)
+	} else {
+		dl.Data, err = hex.DecodeString(req.Data)
+		if err != nil {
+			Response(c, http.StatusBadRequest, 1, "data must be hex", nil)
+			return
+		}
+	}
+
+	if dl.ApplicationID == 0 {
+		dl.ApplicationID = dev.ApplicationID
+	}
+	if dl.ApplicationID == 0 {
+		dl.ApplicationID, err = storage.GetLastApplicationID(dev.DeviceEUI)
+		if err == sql.ErrNoRows {
+			Response(c, http.StatusBadRequest, 1, "please provide application_id, device has no uplink yet", nil)
+			return
+		}
+		if err != nil {
+			Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+			return
+		}
+	}
+
+	dl, err = server.Serv.EnqueueDownlink(dl)
+	if err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", dl)
+}
+
+// @summary 下行数据列表
+// @description 设备最近的下行数据及发送状态
+// @tags device
+// @accept json
+// @produce json
+// @param dev_eui path string true "device eui"
+// @param limit query int false "limit, default 20, max 1000"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /device/{dev_eui}/downlink [get]
+func ListDownlink(c *gin.Context) {
+	var devEUI storage.EUI64
+	if err := devEUI.UnmarshalText([]byte(c.Param("dev_eui"))); err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > maxQueryLimit {
+		Response(c, http.StatusBadRequest, 1, "limit must be >0 and <=1000", nil)
+		return
+	}
+
+	dls, err := storage.GetDownlinks(devEUI, limit)
+	if err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", dls)
+}
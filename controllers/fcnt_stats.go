@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maxiiot/devicebridge/storage"
+)
+
+// @summary 设备帧计数统计
+// @description 设备接收,丢包,重复,重置次数及丢包率
+// @tags device
+// @accept json
+// @produce json
+// @param dev_eui path string true "device eui"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /device/{dev_eui}/fcnt-stats [get]
+func GetFCntStats(c *gin.Context) {
+	var devEUI storage.EUI64
+	if err := devEUI.UnmarshalText([]byte(c.Param("dev_eui"))); err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+
+	stats, err := storage.GetFCntStats(devEUI)
+	if err == sql.ErrNoRows {
+		Response(c, http.StatusNotFound, 1, "device not found", nil)
+		return
+	}
+	if err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", stats)
+}
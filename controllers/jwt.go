@@ -3,6 +3,7 @@ package controllers
 import (
 	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
@@ -16,6 +17,46 @@ var (
 	jwtTTL              = time.Hour * 24
 )
 
+// roleLevels higher level includes permissions of lower levels
+var roleLevels = map[string]int{
+	storage.RoleViewer:   1,
+	storage.RoleOperator: 2,
+	storage.RoleAdmin:    3,
+}
+
+// routeRoles required role of routes, key is "METHOD /request/path".
+// rules use request path instead of route template so they work with any gin version,
+// keep them on static paths or path prefixes.
+// routes not listed require viewer for GET and operator for others,
+// /api/admin/ and /api/users routes require admin
+var routeRoles = map[string]string{
+	"POST /api/user/add":      storage.RoleAdmin,
+	"PUT /api/user/changepwd": storage.RoleViewer,
+}
+
+// changePasswordPath the only route allowed before forced password change
+const changePasswordPath = "/api/user/changepwd"
+
+// requiredRole returns required role of request path
+func requiredRole(method, path string) string {
+	if role, ok := routeRoles[method+" "+path]; ok {
+		return role
+	}
+	if strings.HasPrefix(path, "/api/admin/") || strings.HasPrefix(path, "/api/users") {
+		return storage.RoleAdmin
+	}
+	if method == http.MethodGet || method == http.MethodHead {
+		return storage.RoleViewer
+	}
+	return storage.RoleOperator
+}
+
+// ValidRole check role is admin, operator or viewer
+func ValidRole(role string) bool {
+	_, ok := roleLevels[role]
+	return ok
+}
+
 // SetJWTSecret set jwt secret
 func SetJWTSecret(secret string) {
 	jwtsecret = []byte(secret)
@@ -47,7 +88,7 @@ func ParseToken(tokenStr string) (jwt.MapClaims, error) {
 	return nil, TokenInvalid
 }
 
-// JWTAuth jwt uath middleware
+// JWTAuth jwt uath middleware, checks role of user by route
 func JWTAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := c.Request.Header.Get("Authorization")
@@ -64,9 +105,36 @@ func JWTAuth() gin.HandlerFunc {
 			return
 		}
 
-		if username, ok := claims["username"]; ok {
-			c.Set("username", username)
+		username, _ := claims["username"].(string)
+		// role is loaded on each request so role changes take effect immediately
+		usr, err := storage.LoginUser(username)
+		if err != nil {
+			Response(c, http.StatusUnauthorized, 1, "用户不存在", nil)
+			c.Abort()
+			return
+		}
+
+		if usr.Disabled {
+			Response(c, http.StatusUnauthorized, 1, "用户已禁用", nil)
+			c.Abort()
+			return
+		}
+
+		path := c.Request.URL.Path
+		if usr.MustChangePassword && path != changePasswordPath {
+			Response(c, http.StatusForbidden, 1, "请先修改密码", nil)
+			c.Abort()
+			return
 		}
+
+		if roleLevels[usr.Role] < roleLevels[requiredRole(c.Request.Method, path)] {
+			Response(c, http.StatusForbidden, 1, "无权限访问", nil)
+			c.Abort()
+			return
+		}
+
+		c.Set("username", usr.UserName)
+		c.Set("role", usr.Role)
 		c.Next()
 	}
 }
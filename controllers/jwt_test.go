@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/maxiiot/devicebridge/storage"
+)
+
+func TestRequiredRole(t *testing.T) {
+	for _, c := range []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{"GET", "/api/users", storage.RoleAdmin},
+		{"GET", "/api/users/bob", storage.RoleAdmin},
+		{"PUT", "/api/users/bob/password", storage.RoleAdmin},
+		{"POST", "/api/users/bob/unlock", storage.RoleAdmin},
+		{"POST", "/api/user/add", storage.RoleAdmin},
+		{"PUT", "/api/user/changepwd", storage.RoleViewer},
+		{"POST", "/api/admin/replay", storage.RoleAdmin},
+		{"GET", "/api/device", storage.RoleViewer},
+		{"GET", "/api/device/0102030405060708", storage.RoleViewer},
+		{"HEAD", "/api/device", storage.RoleViewer},
+		{"POST", "/api/device", storage.RoleOperator},
+		{"DELETE", "/api/device/0102030405060708", storage.RoleOperator},
+		{"POST", "/api/devices/import", storage.RoleOperator},
+		{"POST", "/api/alarms/1/ack", storage.RoleOperator},
+	} {
+		if got := requiredRole(c.method, c.path); got != c.want {
+			t.Errorf("%s %s: got %s, want %s", c.method, c.path, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maxiiot/devicebridge/storage"
+)
+
+// @summary 设备测量值
+// @description 设备测量值,按采样时间倒序
+// @tags device
+// @accept json
+// @produce json
+// @param dev_eui path string true "device eui"
+// @param field query string false "field (e.g.: temp)"
+// @param from query string false "sample time from (RFC3339)"
+// @param to query string false "sample time to (RFC3339)"
+// @param limit query int false "limit, default 100, max 1000"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /device/{dev_eui}/measurements [get]
+func ListMeasurement(c *gin.Context) {
+	var devEUI storage.EUI64
+	if err := devEUI.UnmarshalText([]byte(c.Param("dev_eui"))); err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+
+	filter := storage.MeasurementFilter{
+		Field: c.Query("field"),
+	}
+
+	from, err := parseTimeQuery(c, "from")
+	if err != nil {
+		Response(c, http.StatusBadRequest, 1, "from must be RFC3339 time", nil)
+		return
+	}
+	filter.From = from
+
+	to, err := parseTimeQuery(c, "to")
+	if err != nil {
+		Response(c, http.StatusBadRequest, 1, "to must be RFC3339 time", nil)
+		return
+	}
+	filter.To = to
+
+	filter.Limit, err = strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || filter.Limit <= 0 || filter.Limit > maxQueryLimit {
+		Response(c, http.StatusBadRequest, 1, "limit must be >0 and <=1000", nil)
+		return
+	}
+
+	ms, err := storage.GetMeasurements(devEUI, filter)
+	if err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", ms)
+}
+
+// parseTimeQuery parse RFC3339 query param, returns nil when empty
+func parseTimeQuery(c *gin.Context, key string) (*time.Time, error) {
+	v := c.Query(key)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maxiiot/devicebridge/backend/protocol"
+	"github.com/maxiiot/devicebridge/storage"
+)
+
+// @summary 设备协议列表
+// @description 已注册的设备协议解析器,protocol_type可选值
+// @tags protocol
+// @accept json
+// @produce json
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /protocol [get]
+func ListProtocol(c *gin.Context) {
+	decoders := protocol.Decoders()
+	decoders = append(decoders, protocol.Decoder{
+		Name:        storage.ProtocolDefault,
+		Description: "digital device without payload decoding",
+		Fields:      []string{},
+	})
+
+	Response(c, http.StatusOK, 0, "success", decoders)
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maxiiot/devicebridge/storage"
+)
+
+// @summary 校验失败的数据帧统计
+// @description 按设备或协议统计校验失败(帧头,长度,校验码,设备ID)被丢弃的上行数据帧
+// @tags device
+// @accept json
+// @produce json
+// @param dev_eui query string false "device eui"
+// @param protocol query string false "protocol type"
+// @param group_by query string false "device(default) or protocol"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /rejected-frames [get]
+func ListRejectedFrame(c *gin.Context) {
+	filter := storage.RejectedFrameFilter{ProtocolType: c.Query("protocol")}
+	if s := c.Query("dev_eui"); s != "" {
+		var devEUI storage.EUI64
+		if err := devEUI.UnmarshalText([]byte(s)); err != nil {
+			Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+			return
+		}
+		filter.DeviceEUI = &devEUI
+	}
+
+	var (
+		rfs []storage.RejectedFrame
+		err error
+	)
+	switch c.DefaultQuery("group_by", "device") {
+	case "device":
+		rfs, err = storage.GetRejectedFrames(filter)
+	case "protocol":
+		rfs, err = storage.GetRejectedFramesByProtocol(filter)
+	default:
+		Response(c, http.StatusBadRequest, 1, "group_by must be device or protocol", nil)
+		return
+	}
+	if err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", rfs)
+}
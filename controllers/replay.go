@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maxiiot/devicebridge/backend"
+	"github.com/maxiiot/devicebridge/backend/server"
+	"github.com/maxiiot/devicebridge/storage"
+)
+
+// Replay replay request info
+type Replay struct {
+	DeviceEUI string     `json:"device_eui" example:"optional, device eui"`
+	From      *time.Time `json:"from" example:"2019-05-01T00:00:00+08:00"`
+	To        *time.Time `json:"to" example:"2019-05-02T00:00:00+08:00"`
+	Limit     int        `json:"limit" example:"100"`
+	Publish   bool       `json:"publish"`
+	Rewrite   bool       `json:"rewrite"`
+	DryRun    bool       `json:"dry_run"`
+}
+
+// @summary 重放上行数据
+// @description 按设备或时间范围将存储的原始上行数据重新解析,可选重新发布到mqtt或重写测量值,dry_run只返回解析结果
+// @tags admin
+// @accept json
+// @produce json
+// @param replay body controllers.Replay true "replay info"
+// @success 200 {object} controllers.ResponseData
+// @failure 400 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /admin/replay [post]
+func ReplayUplink(c *gin.Context) {
+	var req Replay
+	if err := c.ShouldBind(&req); err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+
+	opts := backend.ReplayOptions{
+		From:    req.From,
+		To:      req.To,
+		Limit:   req.Limit,
+		Publish: req.Publish && !req.DryRun,
+		Rewrite: req.Rewrite && !req.DryRun,
+	}
+	if req.DeviceEUI != "" {
+		var devEUI storage.EUI64
+		if err := devEUI.UnmarshalText([]byte(req.DeviceEUI)); err != nil {
+			Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+			return
+		}
+		opts.DevEUI = &devEUI
+	}
+	if err := opts.Validate(); err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+
+	results, err := server.Serv.Replay(opts)
+	if err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", results)
+}
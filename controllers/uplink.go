@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/maxiiot/devicebridge/storage"
+)
+
+// @summary 设备上行数据日志
+// @description 设备最近的原始上行数据及解析结果
+// @tags device
+// @accept json
+// @produce json
+// @param dev_eui path string true "device eui"
+// @param decode_status query string false "decode status (success/failed/unknown_device/no_decoder/duplicate)"
+// @param limit query int false "limit, default 20, max 1000"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /device/{dev_eui}/uplinks [get]
+func ListUplinkLog(c *gin.Context) {
+	var devEUI storage.EUI64
+	if err := devEUI.UnmarshalText([]byte(c.Param("dev_eui"))); err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > maxQueryLimit {
+		Response(c, http.StatusBadRequest, 1, "limit must be >0 and <=1000", nil)
+		return
+	}
+
+	logs, err := storage.GetUplinkLogs(devEUI, storage.UplinkLogFilter{
+		DecodeStatus: c.Query("decode_status"),
+		Limit:        limit,
+	})
+	if err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", logs)
+}
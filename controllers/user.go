@@ -1,20 +1,47 @@
 package controllers
 
 import (
+	"database/sql"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/maxiiot/devicebridge/storage"
 	"github.com/maxiiot/devicebridge/utils"
 )
 
+const (
+	// maxLoginAttempts consecutive failed logins before user is locked
+	maxLoginAttempts = 5
+	// loginLockDuration lock duration after too many failed logins
+	loginLockDuration = 15 * time.Minute
+)
+
 // User login info
 type User struct {
 	UserName string `json:"user_name" form:"user_name" binding:"required"`
 	Password string `json:"password" form:"password" binding:"required"`
 }
 
+// NewUser create user info
+type NewUser struct {
+	UserName string `json:"user_name" form:"user_name" binding:"required"`
+	Password string `json:"password" form:"password" binding:"required"`
+	Role     string `json:"role" form:"role" example:"optional(admin/operator/viewer), default viewer"`
+}
+
+// UserInfo update user info
+type UserInfo struct {
+	Role     string `json:"role" binding:"required" example:"optional(admin/operator/viewer)"`
+	Disabled bool   `json:"disabled"`
+}
+
+// ResetPassword admin reset user password info
+type ResetPassword struct {
+	Password string `json:"password" binding:"required"`
+}
+
 // UserPassword user change password info
 type UserPassword struct {
 	OldPassword string `json:"old_password" form:"old_password" binding:"required"`
@@ -45,12 +72,31 @@ func Login(c *gin.Context) {
 		return
 	}
 
+	now := time.Now()
+	if usr.Disabled {
+		Response(c, http.StatusForbidden, 1, "用户已禁用", nil)
+		return
+	}
+	if usr.Locked(now) {
+		Response(c, http.StatusForbidden, 1, "登陆失败次数过多,用户已锁定至"+usr.LockedUntil.Format(time.RFC3339), nil)
+		return
+	}
+
 	err = utils.Compare(user.Password, usr.PasswordHash)
 	if err != nil {
+		if err := storage.RecordLoginFailure(usr.UserName, now, maxLoginAttempts, loginLockDuration); err != nil {
+			Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+			return
+		}
 		Response(c, http.StatusBadRequest, 1, "密码错误", nil)
 		return
 	}
 
+	if err := storage.RecordLoginSuccess(usr.UserName, now); err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
 	token, err := CreateToken(usr)
 	if err != nil {
 		msg := fmt.Sprintf("生成JWT错误: %s", err)
@@ -59,12 +105,14 @@ func Login(c *gin.Context) {
 	}
 
 	Response(c, http.StatusOK, 0, "success", gin.H{
-		"jwt": token,
+		"jwt":                  token,
+		"role":                 usr.Role,
+		"must_change_password": usr.MustChangePassword,
 	})
 }
 
 // @summary 更改密码
-// @description 更改密码
+// @description 更改密码,须修改密码的用户修改后才能访问其他接口
 // @tags user
 // @accept json
 // @produce json
@@ -95,11 +143,17 @@ func ChangeUserPassword(c *gin.Context) {
 		return
 	}
 
+	if user_pwd.NewPassword == user_pwd.OldPassword {
+		Response(c, http.StatusBadRequest, 1, "新密码不能与旧密码相同", nil)
+		return
+	}
+
 	olduser.PasswordHash, err = utils.Hash(user_pwd.NewPassword)
 	if err != nil {
 		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
 		return
 	}
+	olduser.MustChangePassword = false
 	err = storage.UpdateUserPassword(olduser)
 	if err != nil {
 		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
@@ -111,23 +165,31 @@ func ChangeUserPassword(c *gin.Context) {
 
 // CreateUser create user
 // @summary 新增用户
-// @description 新增用户
+// @description 新增用户,仅admin可操作
 // @tags user
 // @accept json
 // @produce json
-// @param user body controllers.User true "create user info"
+// @param user body controllers.NewUser true "create user info"
 // @success 200 {object} controllers.ResponseData
 // @failure 500 {object} controllers.ResponseData
 // @security ApiKeyAuth
 // @router /user/add [post]
 func CreateUser(c *gin.Context) {
-	var usr User
+	var usr NewUser
 	err := c.ShouldBind(&usr)
 	if err != nil {
 		Response(c, http.StatusBadRequest, 1, "用户名或密码不能为空", nil)
 		return
 	}
 
+	if usr.Role == "" {
+		usr.Role = storage.RoleViewer
+	}
+	if !ValidRole(usr.Role) {
+		Response(c, http.StatusBadRequest, 1, "role must be admin, operator or viewer", nil)
+		return
+	}
+
 	pwdHash, err := utils.Hash(usr.Password)
 	if err != nil {
 		Response(c, http.StatusBadRequest, 1, "用户名或密码不能为空", nil)
@@ -137,11 +199,215 @@ func CreateUser(c *gin.Context) {
 	err = storage.CreateUser(storage.User{
 		UserName:     usr.UserName,
 		PasswordHash: pwdHash,
+		Role:         usr.Role,
 	})
 	if err != nil {
 		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
 		return
 	}
 
-	Response(c, http.StatusBadRequest, 0, "success", nil)
+	Response(c, http.StatusOK, 0, "success", nil)
+}
+
+// @summary 用户列表
+// @description 用户列表,仅admin可操作
+// @tags user
+// @accept json
+// @produce json
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /users [get]
+func ListUser(c *gin.Context) {
+	users, err := storage.GetUsers()
+	if err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", users)
+}
+
+// @summary 用户明细
+// @description 用户明细,仅admin可操作
+// @tags user
+// @accept json
+// @produce json
+// @param user_name path string true "user name"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /users/{user_name} [get]
+func GetUser(c *gin.Context) {
+	usr, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", usr)
+}
+
+// @summary 修改用户
+// @description 修改用户角色和禁用状态,仅admin可操作,不能禁用或降级最后一个启用的admin
+// @tags user
+// @accept json
+// @produce json
+// @param user_name path string true "user name"
+// @param user body controllers.UserInfo true "update user info"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /users/{user_name} [put]
+func UpdateUser(c *gin.Context) {
+	var info UserInfo
+	if err := c.ShouldBind(&info); err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+	if !ValidRole(info.Role) {
+		Response(c, http.StatusBadRequest, 1, "role must be admin, operator or viewer", nil)
+		return
+	}
+
+	usr, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	if usr.Role == storage.RoleAdmin && !usr.Disabled && (info.Role != storage.RoleAdmin || info.Disabled) {
+		if !checkOtherAdmins(c, usr.UserName) {
+			return
+		}
+	}
+
+	usr.Role = info.Role
+	usr.Disabled = info.Disabled
+	if err := storage.UpdateUser(usr); err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", nil)
+}
+
+// @summary 删除用户
+// @description 删除用户,仅admin可操作,不能删除自己或最后一个启用的admin
+// @tags user
+// @accept json
+// @produce json
+// @param user_name path string true "user name"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /users/{user_name} [delete]
+func DeleteUser(c *gin.Context) {
+	usr, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	if usr.UserName == c.GetString("username") {
+		Response(c, http.StatusConflict, 1, "不能删除当前登陆用户", nil)
+		return
+	}
+	if usr.Role == storage.RoleAdmin && !usr.Disabled && !checkOtherAdmins(c, usr.UserName) {
+		return
+	}
+
+	if err := storage.DeleteUser(usr.UserName); err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", nil)
+}
+
+// @summary 重置密码
+// @description 管理员重置用户密码,同时解除锁定,用户登陆后须修改密码
+// @tags user
+// @accept json
+// @produce json
+// @param user_name path string true "user name"
+// @param password body controllers.ResetPassword true "new password"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /users/{user_name}/password [put]
+func ResetUserPassword(c *gin.Context) {
+	var pwd ResetPassword
+	if err := c.ShouldBind(&pwd); err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+
+	usr, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	var err error
+	usr.PasswordHash, err = utils.Hash(pwd.Password)
+	if err != nil {
+		Response(c, http.StatusBadRequest, 1, err.Error(), nil)
+		return
+	}
+	usr.MustChangePassword = true
+	if err = storage.UpdateUserPassword(usr); err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", nil)
+}
+
+// @summary 解锁用户
+// @description 解除登陆失败过多导致的锁定,仅admin可操作
+// @tags user
+// @accept json
+// @produce json
+// @param user_name path string true "user name"
+// @success 200 {object} controllers.ResponseData
+// @failure 500 {object} controllers.ResponseData
+// @security ApiKeyAuth
+// @router /users/{user_name}/unlock [post]
+func UnlockUser(c *gin.Context) {
+	usr, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	if err := storage.UnlockUser(usr.UserName); err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return
+	}
+
+	Response(c, http.StatusOK, 0, "success", nil)
+}
+
+// userParam get user of user_name path param
+func userParam(c *gin.Context) (storage.User, bool) {
+	usr, err := storage.LoginUser(c.Param("user_name"))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			Response(c, http.StatusNotFound, 1, "用户不存在", nil)
+		} else {
+			Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		}
+		return usr, false
+	}
+	return usr, true
+}
+
+// checkOtherAdmins response 409 if no other enabled admin exists
+func checkOtherAdmins(c *gin.Context, userName string) bool {
+	count, err := storage.CountActiveAdmins(userName)
+	if err != nil {
+		Response(c, http.StatusInternalServerError, 1, err.Error(), nil)
+		return false
+	}
+	if count == 0 {
+		Response(c, http.StatusConflict, 1, "至少保留一个启用的admin", nil)
+		return false
+	}
+	return true
 }
@@ -0,0 +1,16 @@
+-- +migrate Up
+create table measurement(
+    id bigserial primary key,
+    device_eui bytea not null references device on delete cascade,
+    field varchar(50) not null,
+    value double precision,
+    text_value varchar(200),
+    sample_time timestamp with time zone not null,
+    received_at timestamp with time zone not null
+);
+
+create index idx_measurement_device_field_sample_time on measurement(device_eui, field, sample_time);
+
+-- +migrate Down
+drop index idx_measurement_device_field_sample_time;
+drop table measurement;
@@ -0,0 +1,21 @@
+-- +migrate Up
+create table uplink_log(
+    id bigserial primary key,
+    device_eui bytea not null,
+    data bytea not null,
+    f_cnt bigint not null default 0,
+    f_port smallint not null default 0,
+    rx_metadata jsonb not null default '{}',
+    protocol_type varchar(50) not null default '',
+    decode_status varchar(20) not null,
+    decode_error text not null default '',
+    received_at timestamp with time zone not null
+);
+
+create index idx_uplink_log_device_received_at on uplink_log(device_eui, received_at);
+create index idx_uplink_log_received_at on uplink_log(received_at);
+
+-- +migrate Down
+drop index idx_uplink_log_received_at;
+drop index idx_uplink_log_device_received_at;
+drop table uplink_log;
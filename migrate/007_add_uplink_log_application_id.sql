@@ -0,0 +1,5 @@
+-- +migrate Up
+alter table uplink_log add column application_id bigint not null default 0;
+
+-- +migrate Down
+alter table uplink_log drop column application_id;
@@ -0,0 +1,23 @@
+-- +migrate Up
+create table downlink(
+    id bigserial primary key,
+    device_eui bytea not null references device on delete cascade,
+    application_id bigint not null default 0,
+    f_port smallint not null,
+    confirmed boolean not null default false,
+    data bytea not null,
+    command text not null default '',
+    status varchar(20) not null,
+    error text not null default '',
+    created_at timestamp with time zone not null,
+    updated_at timestamp with time zone not null,
+    sent_at timestamp with time zone
+);
+
+create index idx_downlink_device_created_at on downlink(device_eui, created_at);
+create index idx_downlink_status on downlink(status);
+
+-- +migrate Down
+drop index idx_downlink_status;
+drop index idx_downlink_device_created_at;
+drop table downlink;
@@ -0,0 +1,5 @@
+-- +migrate Up
+alter table downlink add column acked_at timestamp with time zone;
+
+-- +migrate Down
+alter table downlink drop column acked_at;
@@ -0,0 +1,13 @@
+-- +migrate Up
+create table rejected_frame(
+    device_eui bytea not null references device on delete cascade,
+    protocol_type varchar(20) not null,
+    reason varchar(20) not null,
+    count bigint not null default 0,
+    last_error text not null default '',
+    last_rejected_at timestamp with time zone not null,
+    primary key(device_eui, protocol_type, reason)
+);
+
+-- +migrate Down
+drop table rejected_frame;
@@ -0,0 +1,15 @@
+-- +migrate Up
+create table alarm_event(
+    id bigserial primary key,
+    device_eui bytea not null references device on delete cascade,
+    protocol_type varchar(20) not null,
+    name varchar(50) not null,
+    sample_time timestamp with time zone not null,
+    received_at timestamp with time zone not null
+);
+
+create index idx_alarm_event_device_sample_time on alarm_event(device_eui, sample_time);
+
+-- +migrate Down
+drop index idx_alarm_event_device_sample_time;
+drop table alarm_event;
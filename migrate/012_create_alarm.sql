@@ -0,0 +1,22 @@
+-- +migrate Up
+create table alarm(
+    id bigserial primary key,
+    device_eui bytea not null references device on delete cascade,
+    protocol_type varchar(20) not null,
+    name varchar(50) not null,
+    status varchar(20) not null,
+    count bigint not null default 1,
+    raised_at timestamp with time zone not null,
+    last_raised_at timestamp with time zone not null,
+    acked_at timestamp with time zone,
+    acked_by varchar(50) not null default '',
+    cleared_at timestamp with time zone
+);
+
+create unique index idx_alarm_device_name_active on alarm(device_eui, name) where status<>'cleared';
+create index idx_alarm_device_raised_at on alarm(device_eui, raised_at);
+
+-- +migrate Down
+drop index idx_alarm_device_raised_at;
+drop index idx_alarm_device_name_active;
+drop table alarm;
@@ -0,0 +1,18 @@
+-- +migrate Up
+alter table device add column report_interval integer not null default 0;
+alter table device add column status varchar(20) not null default 'unknown';
+alter table device add column last_seen_at timestamp with time zone;
+alter table device add column last_fcnt bigint;
+alter table device add column last_rssi integer;
+alter table device add column last_snr double precision;
+
+create index idx_device_status_last_seen_at on device(status, last_seen_at);
+
+-- +migrate Down
+drop index idx_device_status_last_seen_at;
+alter table device drop column last_snr;
+alter table device drop column last_rssi;
+alter table device drop column last_fcnt;
+alter table device drop column last_seen_at;
+alter table device drop column status;
+alter table device drop column report_interval;
@@ -0,0 +1,11 @@
+-- +migrate Up
+alter table device add column fcnt_received bigint not null default 0;
+alter table device add column fcnt_lost bigint not null default 0;
+alter table device add column fcnt_duplicates bigint not null default 0;
+alter table device add column fcnt_resets bigint not null default 0;
+
+-- +migrate Down
+alter table device drop column fcnt_resets;
+alter table device drop column fcnt_duplicates;
+alter table device drop column fcnt_lost;
+alter table device drop column fcnt_received;
@@ -0,0 +1,24 @@
+-- +migrate Up
+alter table device add column name varchar(100) not null default '';
+alter table device add column description text not null default '';
+alter table device add column application_id bigint not null default 0;
+alter table device add column tags text[] not null default '{}';
+alter table device add column location point;
+alter table device add column attributes jsonb not null default '{}';
+
+create index idx_device_name on device(name);
+create index idx_device_application_id on device(application_id);
+create index idx_device_tags on device using gin(tags);
+create index idx_device_attributes on device using gin(attributes);
+
+-- +migrate Down
+drop index idx_device_attributes;
+drop index idx_device_tags;
+drop index idx_device_application_id;
+drop index idx_device_name;
+alter table device drop column attributes;
+alter table device drop column location;
+alter table device drop column tags;
+alter table device drop column application_id;
+alter table device drop column description;
+alter table device drop column name;
@@ -0,0 +1,33 @@
+-- +migrate Up
+create table device_group(
+    id bigserial primary key,
+    parent_id bigint references device_group on delete restrict,
+    name varchar(100) not null,
+    description text not null default '',
+    created_at timestamp with time zone not null,
+    updated_at timestamp with time zone not null
+);
+
+create unique index idx_device_group_parent_name on device_group(coalesce(parent_id, 0), name);
+
+alter table device add column group_id bigint references device_group on delete set null;
+create index idx_device_group_id on device(group_id);
+
+-- +migrate StatementBegin
+create function device_group_path(group_id bigint) returns text as $$
+    with recursive ancestor(id, parent_id, name, depth) as (
+        select id, parent_id, name, 0 from device_group where id = group_id
+        union all
+        select g.id, g.parent_id, g.name, a.depth + 1
+        from device_group g join ancestor a on g.id = a.parent_id
+    )
+    select string_agg(name, '/' order by depth desc) from ancestor
+$$ language sql stable;
+-- +migrate StatementEnd
+
+-- +migrate Down
+drop function device_group_path(bigint);
+drop index idx_device_group_id;
+alter table device drop column group_id;
+drop index idx_device_group_parent_name;
+drop table device_group;
@@ -0,0 +1,11 @@
+-- +migrate Up
+create index idx_device_created_at on device(created_at, device_eui);
+create index idx_device_last_seen_at on device(coalesce(last_seen_at, to_timestamp(0)), device_eui);
+create index idx_device_status on device(status);
+create index idx_device_protocol_type on device(protocol_type);
+
+-- +migrate Down
+drop index idx_device_protocol_type;
+drop index idx_device_status;
+drop index idx_device_last_seen_at;
+drop index idx_device_created_at;
@@ -0,0 +1,8 @@
+-- +migrate Up
+alter table users add column role varchar(20) not null default 'viewer';
+-- users created before roles keep device management, only admin manages users
+update users set role='operator';
+update users set role='admin' where user_name='admin';
+
+-- +migrate Down
+alter table users drop column role;
@@ -0,0 +1,19 @@
+-- +migrate Up
+alter table users add column disabled boolean not null default false;
+alter table users add column failed_logins integer not null default 0;
+alter table users add column locked_until timestamp with time zone;
+alter table users add column last_login_at timestamp with time zone;
+alter table users add column must_change_password boolean not null default false;
+alter table users add column created_at timestamp with time zone not null default now();
+
+-- seeded admin still using the default password must change it on first login
+update users set must_change_password=true
+where user_name='admin' and password_hash='$2a$10$cCLGdc9rmnwTkKdeR6LpHeniqp2ZvI9q6fWC7LDaKUz7dFcnrKBdi';
+
+-- +migrate Down
+alter table users drop column created_at;
+alter table users drop column must_change_password;
+alter table users drop column last_login_at;
+alter table users drop column locked_until;
+alter table users drop column failed_logins;
+alter table users drop column disabled;
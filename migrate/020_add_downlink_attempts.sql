@@ -0,0 +1,7 @@
+-- +migrate Up
+alter table downlink add column attempts integer not null default 0;
+alter table downlink add column retry_at timestamp with time zone;
+
+-- +migrate Down
+alter table downlink drop column retry_at;
+alter table downlink drop column attempts;
@@ -47,14 +47,50 @@ func Route(mode string) *gin.Engine {
 
 	gpRoot := r.Group("/api", controllers.JWTAuth())
 	{
-		gpRoot.GET("/version", controllers.GetVersion) // app version
+		gpRoot.GET("/version", controllers.GetVersion)    // app version
+		gpRoot.GET("/protocol", controllers.ListProtocol) // 设备协议列表
 
 		gpRoot.GET("/device", controllers.ListDevice)               // 设备列表
 		gpRoot.POST("/device", controllers.CreateDevice)            // 新增设备
+		gpRoot.POST("/devices/import", controllers.ImportDevice)    // 批量导入设备
+		gpRoot.GET("/devices/export", controllers.ExportDevice)     // 导出设备
 		gpRoot.GET("/device/:dev_eui", controllers.GetDevice)       // 设备明细
 		gpRoot.PUT("/device", controllers.UpdateDevice)             // 修改设备信息
 		gpRoot.DELETE("/device/:dev_eui", controllers.DeleteDevice) // 删除设备
 
+		gpRoot.GET("/device/:dev_eui/measurements", controllers.ListMeasurement) // 设备测量值
+		gpRoot.GET("/device/:dev_eui/uplinks", controllers.ListUplinkLog)        // 设备上行数据日志
+		gpRoot.GET("/device/:dev_eui/alarm-events", controllers.ListAlarmEvent)  // 设备报警事件
+		gpRoot.GET("/device/:dev_eui/alarms", controllers.ListAlarm)             // 设备报警列表
+		gpRoot.GET("/device/:dev_eui/fcnt-stats", controllers.GetFCntStats)      // 设备帧计数统计
+		gpRoot.GET("/device/:dev_eui/downlink", controllers.ListDownlink)        // 下行数据列表
+		gpRoot.POST("/device/:dev_eui/downlink", controllers.CreateDownlink)     // 发送下行数据
+		gpRoot.GET("/rejected-frames", controllers.ListRejectedFrame)            // 校验失败的数据帧统计
+
+		gpRoot.POST("/alarms/:id/ack", controllers.AckAlarm) // 确认报警
+
+		gpRoot.GET("/device-groups", controllers.ListDeviceGroup)                                 // 设备分组列表
+		gpRoot.POST("/device-groups", controllers.CreateDeviceGroup)                              // 新增设备分组
+		gpRoot.GET("/device-groups/:id", controllers.GetDeviceGroup)                              // 设备分组明细
+		gpRoot.PUT("/device-groups/:id", controllers.UpdateDeviceGroup)                           // 修改设备分组
+		gpRoot.DELETE("/device-groups/:id", controllers.DeleteDeviceGroup)                        // 删除设备分组
+		gpRoot.GET("/device-groups/:id/devices", controllers.ListDeviceGroupDevice)               // 分组设备列表
+		gpRoot.POST("/device-groups/:id/devices", controllers.AddDeviceGroupMember)               // 添加分组设备
+		gpRoot.DELETE("/device-groups/:id/devices/:dev_eui", controllers.RemoveDeviceGroupMember) // 移除分组设备
+		gpRoot.GET("/device-groups/:id/stats", controllers.GetDeviceGroupStats)                   // 设备分组统计
+
+		gpRoot.POST("/admin/replay", controllers.ReplayUplink) // 重放上行数据
+
+	}
+
+	gpUsers := r.Group("/api/users", controllers.JWTAuth())
+	{
+		gpUsers.GET("", controllers.ListUser)                              // 用户列表
+		gpUsers.GET("/:user_name", controllers.GetUser)                    // 用户明细
+		gpUsers.PUT("/:user_name", controllers.UpdateUser)                 // 修改用户角色和禁用状态
+		gpUsers.DELETE("/:user_name", controllers.DeleteUser)              // 删除用户
+		gpUsers.PUT("/:user_name/password", controllers.ResetUserPassword) // 重置密码
+		gpUsers.POST("/:user_name/unlock", controllers.UnlockUser)         // 解锁用户
 	}
 
 	gpUser := r.Group("/api/user")
@@ -0,0 +1,183 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// AlarmStatusOpen alarm raised and waiting for acknowledgement
+	AlarmStatusOpen = "open"
+	// AlarmStatusAcknowledged alarm acknowledged by operator
+	AlarmStatusAcknowledged = "acknowledged"
+	// AlarmStatusCleared alarm cleared by normal frame
+	AlarmStatusCleared = "cleared"
+)
+
+// Alarm define device alarm record, repeats of active alarm are counted in one record
+type Alarm struct {
+	ID           int64      `db:"id" json:"id"`
+	DeviceEUI    EUI64      `db:"device_eui" json:"device_eui"`
+	ProtocolType string     `db:"protocol_type" json:"protocol_type"`
+	Name         string     `db:"name" json:"name"`
+	Status       string     `db:"status" json:"status"`
+	Count        int64      `db:"count" json:"count"`
+	RaisedAt     time.Time  `db:"raised_at" json:"raised_at"`
+	LastRaisedAt time.Time  `db:"last_raised_at" json:"last_raised_at"`
+	AckedAt      *time.Time `db:"acked_at" json:"acked_at"`
+	AckedBy      string     `db:"acked_by" json:"acked_by"`
+	ClearedAt    *time.Time `db:"cleared_at" json:"cleared_at"`
+}
+
+// AlarmFilter filter of alarms query, empty status means open and acknowledged
+type AlarmFilter struct {
+	Status string
+	Limit  int
+}
+
+// RaiseAlarm open alarm of device, or increase count of the active alarm with the same name
+func RaiseAlarm(devEUI EUI64, protocolType, name string, raisedAt time.Time) (Alarm, error) {
+	var alarm Alarm
+	err := sqlx.Get(db, &alarm, `
+		insert into alarm (
+			device_eui,
+			protocol_type,
+			name,
+			status,
+			count,
+			raised_at,
+			last_raised_at
+		)values($1,$2,$3,$4,1,$5,$5)
+		on conflict (device_eui, name) where status<>'cleared' do update set
+		count=alarm.count+1,
+		last_raised_at=greatest(alarm.last_raised_at, excluded.last_raised_at)
+		returning id,
+		device_eui,
+		protocol_type,
+		name,
+		status,
+		count,
+		raised_at,
+		last_raised_at,
+		acked_at,
+		acked_by,
+		cleared_at`,
+		devEUI,
+		protocolType,
+		name,
+		AlarmStatusOpen,
+		raisedAt,
+	)
+	return alarm, err
+}
+
+// AckAlarm acknowledge open alarm, returns sql.ErrNoRows if the alarm is not open
+func AckAlarm(id int64, username string) (Alarm, error) {
+	var alarm Alarm
+	err := sqlx.Get(db, &alarm, `
+		update alarm set
+		status=$2,
+		acked_at=$3,
+		acked_by=$4
+		where id=$1 and status=$5
+		returning id,
+		device_eui,
+		protocol_type,
+		name,
+		status,
+		count,
+		raised_at,
+		last_raised_at,
+		acked_at,
+		acked_by,
+		cleared_at`,
+		id,
+		AlarmStatusAcknowledged,
+		time.Now(),
+		username,
+		AlarmStatusOpen,
+	)
+	return alarm, err
+}
+
+// ClearAlarms clear open and acknowledged alarms of device, returns cleared count
+func ClearAlarms(devEUI EUI64, clearedAt time.Time) (int64, error) {
+	res, err := db.Exec(`
+		update alarm set
+		status=$2,
+		cleared_at=$3
+		where device_eui=$1 and status<>$2`,
+		devEUI,
+		AlarmStatusCleared,
+		clearedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// GetAlarm get alarm by id
+func GetAlarm(id int64) (Alarm, error) {
+	var alarm Alarm
+	err := sqlx.Get(db, &alarm, `
+		select id,
+		device_eui,
+		protocol_type,
+		name,
+		status,
+		count,
+		raised_at,
+		last_raised_at,
+		acked_at,
+		acked_by,
+		cleared_at
+		from alarm
+		where id=$1`,
+		id,
+	)
+	return alarm, err
+}
+
+// GetAlarms get device alarms order by last raised time desc
+func GetAlarms(devEUI EUI64, filter AlarmFilter) ([]Alarm, error) {
+	where := []string{"device_eui=$1"}
+	args := []interface{}{devEUI}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		where = append(where, fmt.Sprintf("status=$%d", len(args)))
+	} else {
+		args = append(args, AlarmStatusCleared)
+		where = append(where, fmt.Sprintf("status<>$%d", len(args)))
+	}
+	args = append(args, filter.Limit)
+
+	alarms := []Alarm{}
+	err := sqlx.Select(db, &alarms, fmt.Sprintf(`
+		select id,
+		device_eui,
+		protocol_type,
+		name,
+		status,
+		count,
+		raised_at,
+		last_raised_at,
+		acked_at,
+		acked_by,
+		cleared_at
+		from alarm
+		where %s
+		order by last_raised_at desc, id desc
+		limit $%d`,
+		strings.Join(where, " and "),
+		len(args),
+	), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return alarms, nil
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// AlarmEvent define device alarm reported by decoder
+type AlarmEvent struct {
+	ID           int64     `db:"id" json:"id"`
+	DeviceEUI    EUI64     `db:"device_eui" json:"device_eui"`
+	ProtocolType string    `db:"protocol_type" json:"protocol_type"`
+	Name         string    `db:"name" json:"name"`
+	SampleTime   time.Time `db:"sample_time" json:"sample_time"`
+	ReceivedAt   time.Time `db:"received_at" json:"received_at"`
+}
+
+// AlarmEventFilter filter of alarm events query
+type AlarmEventFilter struct {
+	Name  string
+	From  *time.Time
+	To    *time.Time
+	Limit int
+}
+
+// CreateAlarmEvents create alarm events in one transaction
+func CreateAlarmEvents(events []AlarmEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range events {
+		_, err = tx.Exec(`
+			insert into alarm_event (
+				device_eui,
+				protocol_type,
+				name,
+				sample_time,
+				received_at
+			)values($1,$2,$3,$4,$5)`,
+			e.DeviceEUI,
+			e.ProtocolType,
+			e.Name,
+			e.SampleTime,
+			e.ReceivedAt,
+		)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetAlarmEvents get device alarm events order by sample time desc
+func GetAlarmEvents(devEUI EUI64, filter AlarmEventFilter) ([]AlarmEvent, error) {
+	where := []string{"device_eui=$1"}
+	args := []interface{}{devEUI}
+	if filter.Name != "" {
+		args = append(args, filter.Name)
+		where = append(where, fmt.Sprintf("name=$%d", len(args)))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		where = append(where, fmt.Sprintf("sample_time>=$%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		where = append(where, fmt.Sprintf("sample_time<=$%d", len(args)))
+	}
+	args = append(args, filter.Limit)
+
+	events := []AlarmEvent{}
+	err := sqlx.Select(db, &events, fmt.Sprintf(`
+		select id,
+		device_eui,
+		protocol_type,
+		name,
+		sample_time,
+		received_at
+		from alarm_event
+		where %s
+		order by sample_time desc, id desc
+		limit $%d`,
+		strings.Join(where, " and "),
+		len(args),
+	), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
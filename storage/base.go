@@ -3,6 +3,7 @@ package storage
 import (
 	"database/sql/driver"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -54,8 +55,8 @@ func (e EUI64) String() string {
 
 // GPSPoint contains a GPS point.
 type GPSPoint struct {
-	Latitude  float64
-	Longitude float64
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
 }
 
 // Value implements the driver.Valuer interface
@@ -76,3 +77,61 @@ func (p *GPSPoint) Scan(src interface{}) error {
 	_, err := fmt.Sscanf(string(b), "(%f,%f)", &p.Latitude, &p.Longitude)
 	return err
 }
+
+// HexBytes bytes with hex json format
+type HexBytes []byte
+
+// Scan implements sql.Scanner.
+func (h *HexBytes) Scan(src interface{}) error {
+	b, ok := src.([]byte)
+	if !ok {
+		return errors.New("[]byte type expected")
+	}
+	*h = make(HexBytes, len(b))
+	copy(*h, b)
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (h HexBytes) Value() (driver.Value, error) {
+	return []byte(h), nil
+}
+
+// UnmarshalText unmarshal hex string to bytes
+func (h *HexBytes) UnmarshalText(text []byte) error {
+	b, err := hex.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	*h = b
+	return nil
+}
+
+// MarshalText implement json marshal
+func (h HexBytes) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(h)), nil
+}
+
+// Attributes custom key/value attributes stored as jsonb
+type Attributes map[string]string
+
+// Scan implements sql.Scanner.
+func (a *Attributes) Scan(src interface{}) error {
+	b, ok := src.([]byte)
+	if !ok {
+		return errors.New("[]byte type expected")
+	}
+	return json.Unmarshal(b, a)
+}
+
+// Value implements driver.Valuer.
+func (a Attributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
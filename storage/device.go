@@ -3,9 +3,15 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 const (
@@ -13,29 +19,201 @@ const (
 	ProtocolHumiture = "humiture"
 	// ProtocolSmoke smoke protocol
 	ProtocolSmoke = "smoke"
+	// ProtocolAngus angus livestock tracker protocol
+	ProtocolAngus = "angus"
+	// ProtocolMaxiiot maxiiot frame, sensor type detected by device id
+	ProtocolMaxiiot = "maxiiot"
 	// ProtocolDefault default protocol
 	ProtocolDefault = "digital"
 )
 
+const (
+	// DeviceStatusUnknown device has not been seen since liveness tracking
+	DeviceStatusUnknown = "unknown"
+	// DeviceStatusOnline device reported within expected interval
+	DeviceStatusOnline = "online"
+	// DeviceStatusOffline device missed reporting intervals
+	DeviceStatusOffline = "offline"
+)
+
 // Device define device model
 type Device struct {
-	DeviceEUI    EUI64     `db:"device_eui" json:"device_eui"`
-	ProtocolType string    `db:"protocol_type" json:"protocol_type"`
-	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	DeviceEUI      EUI64          `db:"device_eui" json:"device_eui"`
+	ProtocolType   string         `db:"protocol_type" json:"protocol_type"`
+	Name           string         `db:"name" json:"name"`
+	Description    string         `db:"description" json:"description"`
+	ApplicationID  int64          `db:"application_id" json:"application_id"` // lora应用ID
+	Tags           pq.StringArray `db:"tags" json:"tags"`
+	Location       *GPSPoint      `db:"location" json:"location"` // 安装位置
+	Attributes     Attributes     `db:"attributes" json:"attributes"`
+	GroupID        *int64         `db:"group_id" json:"group_id"`               // 所属分组
+	GroupPath      string         `db:"group_path" json:"group_path"`           // 分组路径,如site/building/floor
+	ReportInterval int            `db:"report_interval" json:"report_interval"` // 预期上报间隔(秒),0表示不检测离线
+	Status         string         `db:"status" json:"status"`
+	LastSeenAt     *time.Time     `db:"last_seen_at" json:"last_seen_at"`
+	LastFCnt       *int64         `db:"last_fcnt" json:"last_fcnt"`
+	LastRSSI       *int32         `db:"last_rssi" json:"last_rssi"`
+	LastSNR        *float64       `db:"last_snr" json:"last_snr"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+}
+
+// DeviceFilter filter of devices query, empty fields are ignored
+type DeviceFilter struct {
+	Name          string       // 名称包含
+	ApplicationID int64        // lora应用ID
+	Tag           string       // 包含标签
+	Attributes    Attributes   // 包含全部属性
+	Within        *[2]GPSPoint // 位置在两点构成的矩形内
+	GroupID       int64        // 分组,包含下级分组的设备
+	ProtocolType  string       // 协议类型
+	EUIPrefix     string       // EUI前缀(hex)
+	Status        string       // 在线状态
+	CreatedFrom   *time.Time   // 创建时间起
+	CreatedTo     *time.Time   // 创建时间止
+}
+
+const (
+	// DeviceOrderCreated order devices by created time
+	DeviceOrderCreated = "created_at"
+	// DeviceOrderLastSeen order devices by last seen time, never seen devices as epoch
+	DeviceOrderLastSeen = "last_seen_at"
+	// DeviceOrderEUI order devices by device eui
+	DeviceOrderEUI = "device_eui"
+)
+
+// DeviceOrder order of devices query, device eui is always the tie breaker
+type DeviceOrder struct {
+	By    string        // 排序字段,默认created_at
+	Desc  bool          // 倒序
+	After *DeviceCursor // 游标,返回游标之后的设备
+}
+
+// DeviceCursor position of device in ordered list
+type DeviceCursor struct {
+	Value     *time.Time `json:"v,omitempty"` // 排序字段值,按eui排序时为空
+	DeviceEUI EUI64      `json:"e"`
+}
+
+// String encode cursor as url safe string
+func (c DeviceCursor) String() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// ParseDeviceCursor decode cursor from DeviceCursor.String
+func ParseDeviceCursor(s string) (DeviceCursor, error) {
+	var c DeviceCursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, errors.New("invalid cursor")
+	}
+	if err = json.Unmarshal(b, &c); err != nil {
+		return c, errors.New("invalid cursor")
+	}
+	return c, nil
+}
+
+// expr returns sort expression of order
+func (o DeviceOrder) expr() string {
+	switch o.By {
+	case DeviceOrderLastSeen:
+		return "coalesce(last_seen_at, to_timestamp(0))"
+	case DeviceOrderEUI:
+		return "device_eui"
+	default:
+		return "created_at"
+	}
+}
+
+// Cursor returns cursor of device in order
+func (o DeviceOrder) Cursor(dev Device) DeviceCursor {
+	c := DeviceCursor{DeviceEUI: dev.DeviceEUI}
+	switch o.By {
+	case DeviceOrderLastSeen:
+		t := time.Unix(0, 0)
+		if dev.LastSeenAt != nil {
+			t = *dev.LastSeenAt
+		}
+		c.Value = &t
+	case DeviceOrderEUI:
+	default:
+		t := dev.CreatedAt
+		c.Value = &t
+	}
+	return c
 }
 
+// deviceColumns columns of device model
+const deviceColumns = `device_eui,
+		protocol_type,
+		name,
+		description,
+		application_id,
+		tags,
+		location,
+		attributes,
+		group_id,
+		coalesce(device_group_path(group_id), '') as group_path,
+		report_interval,
+		status,
+		last_seen_at,
+		last_fcnt,
+		last_rssi,
+		last_snr,
+		created_at`
+
 // CreateDevice create device on database
 func CreateDevice(dev Device) error {
+	return createDevice(db, dev, time.Now())
+}
+
+// CreateDevices create devices in one transaction, returns index of the failed device
+func CreateDevices(devs []Device) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return -1, err
+	}
+
 	now := time.Now()
-	_, err := db.Exec(`
+	for i, dev := range devs {
+		if err = createDevice(tx, dev, now); err != nil {
+			tx.Rollback()
+			return i, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return -1, err
+	}
+	return -1, nil
+}
+
+func createDevice(e sqlx.Execer, dev Device, now time.Time) error {
+	_, err := e.Exec(`
 		insert into device (
 			device_eui,
 			protocol_type,
+			name,
+			description,
+			application_id,
+			tags,
+			location,
+			attributes,
+			report_interval,
 			created_at,
 			updated_at
-		)values($1,$2,$3,$3)`,
+		)values($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$10)`,
 		dev.DeviceEUI,
 		dev.ProtocolType,
+		dev.Name,
+		dev.Description,
+		dev.ApplicationID,
+		dev.tags(),
+		dev.Location,
+		dev.Attributes,
+		dev.ReportInterval,
 		now,
 	)
 	return err
@@ -51,9 +229,7 @@ func GetDeviceByEUI(eui string) (Device, error) {
 	}
 
 	err = sqlx.Get(db, &dev, `
-		select device_eui,
-		protocol_type,
-		created_at
+		select `+deviceColumns+`
 		from device
 		where device_eui=$1`,
 		devEUI,
@@ -69,10 +245,26 @@ func GetDeviceByEUI(eui string) (Device, error) {
 func UpdateDevice(dev Device) error {
 	_, err := db.Exec(`
 		update device set
-		protocol_type=$2
+		protocol_type=$2,
+		name=$3,
+		description=$4,
+		application_id=$5,
+		tags=$6,
+		location=$7,
+		attributes=$8,
+		report_interval=$9,
+		updated_at=$10
 		where device_eui=$1`,
 		dev.DeviceEUI,
 		dev.ProtocolType,
+		dev.Name,
+		dev.Description,
+		dev.ApplicationID,
+		dev.tags(),
+		dev.Location,
+		dev.Attributes,
+		dev.ReportInterval,
+		time.Now(),
 	)
 
 	return err
@@ -115,17 +307,64 @@ func delteDeviceInfo(tx *sql.Tx, devEUI EUI64) (err error) {
 	return nil
 }
 
-// GetDevices get devices.
-func GetDevices(limit, offset int) ([]Device, error) {
-	var devs []Device
+// GetDevices get devices in order, offset is ignored when order has cursor.
+func GetDevices(filter DeviceFilter, order DeviceOrder, limit, offset int) ([]Device, error) {
+	where, args := deviceWhere(filter)
+
+	dir, cmp := "asc", ">"
+	if order.Desc {
+		dir, cmp = "desc", "<"
+	}
+	expr := order.expr()
+	if order.After != nil {
+		if expr == "device_eui" {
+			args = append(args, order.After.DeviceEUI)
+			where += fmt.Sprintf(" and device_eui %s $%d", cmp, len(args))
+		} else {
+			if order.After.Value == nil {
+				return nil, errors.New("invalid cursor")
+			}
+			args = append(args, *order.After.Value, order.After.DeviceEUI)
+			where += fmt.Sprintf(" and (%s, device_eui) %s ($%d, $%d)", expr, cmp, len(args)-1, len(args))
+		}
+		offset = 0
+	}
+	orderBy := "device_eui " + dir
+	if expr != "device_eui" {
+		orderBy = expr + " " + dir + ", " + orderBy
+	}
+	args = append(args, limit, offset)
+
+	devs := []Device{}
+	err := sqlx.Select(db, &devs, fmt.Sprintf(`
+		select `+deviceColumns+`
+		from device
+		where %s
+		order by %s
+		limit $%d offset $%d`,
+		where,
+		orderBy,
+		len(args)-1,
+		len(args),
+	), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return devs, nil
+}
+
+// GetAllDevices get all devices matching filter order by device eui
+func GetAllDevices(filter DeviceFilter) ([]Device, error) {
+	where, args := deviceWhere(filter)
+
+	devs := []Device{}
 	err := sqlx.Select(db, &devs, `
-		select d.device_eui,
-		d.protocol_type,
-		d.created_at
-		from device d 
-		limit $1 offset $2`,
-		limit,
-		offset,
+		select `+deviceColumns+`
+		from device
+		where `+where+`
+		order by device_eui`,
+		args...,
 	)
 	if err != nil {
 		return nil, err
@@ -134,6 +373,57 @@ func GetDevices(limit, offset int) ([]Device, error) {
 	return devs, nil
 }
 
+// deviceWhere returns where clause and args of device filter
+func deviceWhere(filter DeviceFilter) (string, []interface{}) {
+	where := []string{"true"}
+	var args []interface{}
+	if filter.Name != "" {
+		args = append(args, "%"+filter.Name+"%")
+		where = append(where, fmt.Sprintf("name ilike $%d", len(args)))
+	}
+	if filter.ApplicationID != 0 {
+		args = append(args, filter.ApplicationID)
+		where = append(where, fmt.Sprintf("application_id=$%d", len(args)))
+	}
+	if filter.Tag != "" {
+		args = append(args, pq.StringArray{filter.Tag})
+		where = append(where, fmt.Sprintf("tags @> $%d", len(args)))
+	}
+	if len(filter.Attributes) > 0 {
+		args = append(args, filter.Attributes)
+		where = append(where, fmt.Sprintf("attributes @> $%d::jsonb", len(args)))
+	}
+	if filter.Within != nil {
+		args = append(args, filter.Within[0], filter.Within[1])
+		where = append(where, fmt.Sprintf("location <@ box($%d::point,$%d::point)", len(args)-1, len(args)))
+	}
+	if filter.ProtocolType != "" {
+		args = append(args, filter.ProtocolType)
+		where = append(where, fmt.Sprintf("protocol_type=$%d", len(args)))
+	}
+	if filter.EUIPrefix != "" {
+		args = append(args, strings.ToLower(filter.EUIPrefix)+"%")
+		where = append(where, fmt.Sprintf("encode(device_eui, 'hex') like $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		where = append(where, fmt.Sprintf("status=$%d", len(args)))
+	}
+	if filter.CreatedFrom != nil {
+		args = append(args, *filter.CreatedFrom)
+		where = append(where, fmt.Sprintf("created_at>=$%d", len(args)))
+	}
+	if filter.CreatedTo != nil {
+		args = append(args, *filter.CreatedTo)
+		where = append(where, fmt.Sprintf("created_at<=$%d", len(args)))
+	}
+	if filter.GroupID != 0 {
+		args = append(args, filter.GroupID)
+		where = append(where, "group_id in ("+fmt.Sprintf(groupTreeSQL, len(args))+")")
+	}
+	return strings.Join(where, " and "), args
+}
+
 // GetDevicesEUI get all devices eui
 func GetDevicesEUI() ([]string, error) {
 	var euis []EUI64
@@ -155,11 +445,15 @@ func GetDevicesEUI() ([]string, error) {
 }
 
 // GetDevicesCount get count of device.
-func GetDevicesCount() (int, error) {
+func GetDevicesCount(filter DeviceFilter) (int, error) {
+	where, args := deviceWhere(filter)
+
 	var count int
 	err := sqlx.Get(db, &count, `
 		select count(device_eui) as cnt
-		from device`,
+		from device
+		where `+where,
+		args...,
 	)
 
 	if err != nil {
@@ -168,3 +462,56 @@ func GetDevicesCount() (int, error) {
 
 	return count, nil
 }
+
+// tags returns empty array instead of null
+func (dev Device) tags() pq.StringArray {
+	if dev.Tags == nil {
+		return pq.StringArray{}
+	}
+	return dev.Tags
+}
+
+// TouchDevice record device seen by uplink and mark it online, returns status before,
+// nil fcnt keeps the last frame counter
+func TouchDevice(devEUI EUI64, seenAt time.Time, fcnt *uint32, rssi int32, snr float64) (string, error) {
+	var status string
+	err := sqlx.Get(db, &status, `
+		update device d set
+		status=$2,
+		last_seen_at=$3,
+		last_fcnt=coalesce($4, d.last_fcnt),
+		last_rssi=$5,
+		last_snr=$6
+		from device old
+		where d.device_eui=$1 and old.device_eui=d.device_eui
+		returning old.status`,
+		devEUI,
+		DeviceStatusOnline,
+		seenAt,
+		fcnt,
+		rssi,
+		snr,
+	)
+	return status, err
+}
+
+// MarkOfflineDevices mark online devices offline which missed n report intervals, returns marked devices
+func MarkOfflineDevices(n int, now time.Time) ([]Device, error) {
+	devs := []Device{}
+	err := sqlx.Select(db, &devs, `
+		update device set
+		status=$1
+		where status=$2
+		and report_interval>0
+		and last_seen_at < $3::timestamptz - make_interval(secs => report_interval * $4::integer)
+		returning `+deviceColumns,
+		DeviceStatusOffline,
+		DeviceStatusOnline,
+		now,
+		n,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return devs, nil
+}
@@ -0,0 +1,242 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrGroupCycle group moved under itself or its descendant
+	ErrGroupCycle = errors.New("group can not be moved under itself or its descendant")
+	// ErrGroupNotEmpty group still has child groups
+	ErrGroupNotEmpty = errors.New("group has child groups")
+	// ErrGroupExists group with same name exists under parent
+	ErrGroupExists = errors.New("group name already exists under parent")
+)
+
+// groupTreeSQL subquery of group and its descendant group ids, %d is placeholder index of group id
+const groupTreeSQL = `with recursive tree(id) as (
+			select id from device_group where id=$%d
+			union all
+			select g.id from device_group g join tree t on g.parent_id=t.id
+		) select id from tree`
+
+// DeviceGroup define device group, e.g. site/building/floor
+type DeviceGroup struct {
+	ID          int64     `db:"id" json:"id"`
+	ParentID    *int64    `db:"parent_id" json:"parent_id"` // 上级分组,空表示顶级分组
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	Path        string    `db:"path" json:"path"` // 分组路径,如site/building/floor
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// DeviceGroupStats aggregates of devices in group and its descendants
+type DeviceGroupStats struct {
+	Devices    int `db:"devices" json:"devices"`
+	Online     int `db:"online" json:"online"`
+	Offline    int `db:"offline" json:"offline"`
+	OpenAlarms int `db:"open_alarms" json:"open_alarms"`
+}
+
+const deviceGroupColumns = `id,
+		parent_id,
+		name,
+		description,
+		device_group_path(id) as path,
+		created_at,
+		updated_at`
+
+// CreateDeviceGroup create device group
+func CreateDeviceGroup(g DeviceGroup) (DeviceGroup, error) {
+	now := time.Now()
+	var id int64
+	err := sqlx.Get(db, &id, `
+		insert into device_group (
+			parent_id,
+			name,
+			description,
+			created_at,
+			updated_at
+		)values($1,$2,$3,$4,$4)
+		returning id`,
+		g.ParentID,
+		g.Name,
+		g.Description,
+		now,
+	)
+	if err != nil {
+		return g, groupError(err)
+	}
+	return GetDeviceGroup(id)
+}
+
+// GetDeviceGroup get device group by id
+func GetDeviceGroup(id int64) (DeviceGroup, error) {
+	var g DeviceGroup
+	err := sqlx.Get(db, &g, `
+		select `+deviceGroupColumns+`
+		from device_group
+		where id=$1`,
+		id,
+	)
+	return g, err
+}
+
+// GetDeviceGroups get child groups of parent, nil parent means top level groups
+func GetDeviceGroups(parentID *int64) ([]DeviceGroup, error) {
+	groups := []DeviceGroup{}
+	err := sqlx.Select(db, &groups, `
+		select `+deviceGroupColumns+`
+		from device_group
+		where parent_id is not distinct from $1
+		order by name`,
+		parentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// UpdateDeviceGroup update device group, returns ErrGroupCycle if parent is the group or its descendant,
+// sql.ErrNoRows if group not exists
+func UpdateDeviceGroup(g DeviceGroup) (DeviceGroup, error) {
+	if g.ParentID != nil {
+		var cycle bool
+		err := sqlx.Get(db, &cycle, `
+			select $2::bigint in (`+fmt.Sprintf(groupTreeSQL, 1)+`)`,
+			g.ID,
+			*g.ParentID,
+		)
+		if err != nil {
+			return g, err
+		}
+		if cycle {
+			return g, ErrGroupCycle
+		}
+	}
+
+	res, err := db.Exec(`
+		update device_group set
+		parent_id=$2,
+		name=$3,
+		description=$4,
+		updated_at=$5
+		where id=$1`,
+		g.ID,
+		g.ParentID,
+		g.Name,
+		g.Description,
+		time.Now(),
+	)
+	if err != nil {
+		return g, groupError(err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return g, sql.ErrNoRows
+	}
+	return GetDeviceGroup(g.ID)
+}
+
+// DeleteDeviceGroup delete group without child groups, devices of the group become ungrouped
+func DeleteDeviceGroup(id int64) error {
+	var children int
+	err := sqlx.Get(db, &children, `
+		select count(*)
+		from device_group
+		where parent_id=$1`,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return ErrGroupNotEmpty
+	}
+
+	_, err = db.Exec(`
+		delete from device_group
+		where id=$1`,
+		id,
+	)
+	return err
+}
+
+// AddDeviceGroupMembers move devices into group, returns count of devices updated
+func AddDeviceGroupMembers(id int64, devEUIs []EUI64) (int64, error) {
+	res, err := db.Exec(`
+		update device set
+		group_id=$1,
+		updated_at=$3
+		where device_eui=any($2)`,
+		id,
+		euiArray(devEUIs),
+		time.Now(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// RemoveDeviceGroupMembers remove devices from group, returns count of devices updated
+func RemoveDeviceGroupMembers(id int64, devEUIs []EUI64) (int64, error) {
+	res, err := db.Exec(`
+		update device set
+		group_id=null,
+		updated_at=$3
+		where group_id=$1 and device_eui=any($2)`,
+		id,
+		euiArray(devEUIs),
+		time.Now(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// GetDeviceGroupStats get aggregates of group and its descendants
+func GetDeviceGroupStats(id int64) (DeviceGroupStats, error) {
+	var stats DeviceGroupStats
+	err := sqlx.Get(db, &stats, `
+		select count(*) as devices,
+		count(*) filter (where d.status=$2) as online,
+		count(*) filter (where d.status=$3) as offline,
+		coalesce(sum((
+			select count(*) from alarm a
+			where a.device_eui=d.device_eui and a.status=$4
+		)), 0) as open_alarms
+		from device d
+		where d.group_id in (`+fmt.Sprintf(groupTreeSQL, 1)+`)`,
+		id,
+		DeviceStatusOnline,
+		DeviceStatusOffline,
+		AlarmStatusOpen,
+	)
+	return stats, err
+}
+
+// groupError convert unique violation to ErrGroupExists
+func groupError(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return ErrGroupExists
+	}
+	return err
+}
+
+// euiArray convert euis to postgres bytea array
+func euiArray(devEUIs []EUI64) pq.ByteaArray {
+	arr := make(pq.ByteaArray, 0, len(devEUIs))
+	for i := range devEUIs {
+		arr = append(arr, devEUIs[i][:])
+	}
+	return arr
+}
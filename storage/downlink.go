@@ -0,0 +1,231 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// DownlinkStatusPending downlink waiting to be sent
+	DownlinkStatusPending = "pending"
+	// DownlinkStatusSent downlink enqueued to lora network server
+	DownlinkStatusSent = "sent"
+	// DownlinkStatusFailed downlink send error or device executes failed
+	DownlinkStatusFailed = "failed"
+	// DownlinkStatusAcknowledged downlink acknowledged by device
+	DownlinkStatusAcknowledged = "acknowledged"
+)
+
+// Downlink define downlink queue item
+type Downlink struct {
+	ID            int64      `db:"id" json:"id"`
+	DeviceEUI     EUI64      `db:"device_eui" json:"device_eui"`
+	ApplicationID int64      `db:"application_id" json:"application_id"`
+	FPort         uint8      `db:"f_port" json:"fport"`
+	Confirmed     bool       `db:"confirmed" json:"confirmed"`
+	Data          HexBytes   `db:"data" json:"data"`
+	Command       string     `db:"command" json:"command"`
+	Status        string     `db:"status" json:"status"`
+	Error         string     `db:"error" json:"error"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at" json:"updated_at"`
+	SentAt        *time.Time `db:"sent_at" json:"sent_at"`
+	AckedAt       *time.Time `db:"acked_at" json:"acked_at"`
+	Attempts      int        `db:"attempts" json:"attempts"` // 发送失败次数
+	RetryAt       *time.Time `db:"retry_at" json:"retry_at"` // 下次重试时间
+}
+
+// CreateDownlink create pending downlink, returns downlink with id
+func CreateDownlink(dl Downlink) (Downlink, error) {
+	now := time.Now()
+	dl.Status = DownlinkStatusPending
+	dl.CreatedAt = now
+	dl.UpdatedAt = now
+	err := sqlx.Get(db, &dl.ID, `
+		insert into downlink (
+			device_eui,
+			application_id,
+			f_port,
+			confirmed,
+			data,
+			command,
+			status,
+			created_at,
+			updated_at
+		)values($1,$2,$3,$4,$5,$6,$7,$8,$8)
+		returning id`,
+		dl.DeviceEUI,
+		dl.ApplicationID,
+		dl.FPort,
+		dl.Confirmed,
+		dl.Data,
+		dl.Command,
+		dl.Status,
+		now,
+	)
+	return dl, err
+}
+
+// GetDownlink get downlink by id
+func GetDownlink(id int64) (Downlink, error) {
+	var dl Downlink
+	err := sqlx.Get(db, &dl, `
+		select id,
+		device_eui,
+		application_id,
+		f_port,
+		confirmed,
+		data,
+		command,
+		status,
+		error,
+		created_at,
+		updated_at,
+		sent_at,
+		acked_at,
+		attempts,
+		retry_at
+		from downlink
+		where id=$1`,
+		id,
+	)
+	return dl, err
+}
+
+// GetDownlinks get device recent downlinks
+func GetDownlinks(devEUI EUI64, limit int) ([]Downlink, error) {
+	dls := []Downlink{}
+	err := sqlx.Select(db, &dls, `
+		select id,
+		device_eui,
+		application_id,
+		f_port,
+		confirmed,
+		data,
+		command,
+		status,
+		error,
+		created_at,
+		updated_at,
+		sent_at,
+		acked_at,
+		attempts,
+		retry_at
+		from downlink
+		where device_eui=$1
+		order by created_at desc, id desc
+		limit $2`,
+		devEUI,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return dls, nil
+}
+
+// GetPendingDownlinkIDs get ids of pending downlinks due to send before now order by created time
+func GetPendingDownlinkIDs(now time.Time) ([]int64, error) {
+	var ids []int64
+	err := sqlx.Select(db, &ids, `
+		select id
+		from downlink
+		where status=$1 and (retry_at is null or retry_at<=$2)
+		order by created_at, id`,
+		DownlinkStatusPending,
+		now,
+	)
+	return ids, err
+}
+
+// UpdateDownlinkStatus update downlink status and error
+func UpdateDownlinkStatus(id int64, status, errText string) error {
+	now := time.Now()
+	var sentAt *time.Time
+	if status == DownlinkStatusSent {
+		sentAt = &now
+	}
+	_, err := db.Exec(`
+		update downlink set
+		status=$2,
+		error=$3,
+		updated_at=$4,
+		sent_at=coalesce($5,sent_at)
+		where id=$1`,
+		id,
+		status,
+		errText,
+		now,
+		sentAt,
+	)
+	return err
+}
+
+// RetryDownlink keep downlink pending after send error, increase attempts and set next retry time
+func RetryDownlink(id int64, errText string, retryAt time.Time) error {
+	_, err := db.Exec(`
+		update downlink set
+		error=$2,
+		attempts=attempts+1,
+		retry_at=$3,
+		updated_at=$4
+		where id=$1 and status=$5`,
+		id,
+		errText,
+		retryAt,
+		time.Now(),
+		DownlinkStatusPending,
+	)
+	return err
+}
+
+// GetSentDownlinks get device downlinks waiting for acknowledgement order by sent time
+func GetSentDownlinks(devEUI EUI64) ([]Downlink, error) {
+	dls := []Downlink{}
+	err := sqlx.Select(db, &dls, `
+		select id,
+		device_eui,
+		application_id,
+		f_port,
+		confirmed,
+		data,
+		command,
+		status,
+		error,
+		created_at,
+		updated_at,
+		sent_at,
+		acked_at,
+		attempts,
+		retry_at
+		from downlink
+		where device_eui=$1 and status=$2
+		order by sent_at, id`,
+		devEUI,
+		DownlinkStatusSent,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return dls, nil
+}
+
+// AckDownlink update status of sent downlink by device acknowledgement
+func AckDownlink(id int64, status, errText string) error {
+	now := time.Now()
+	_, err := db.Exec(`
+		update downlink set
+		status=$2,
+		error=$3,
+		updated_at=$4,
+		acked_at=$4
+		where id=$1 and status=$5`,
+		id,
+		status,
+		errText,
+		now,
+		DownlinkStatusSent,
+	)
+	return err
+}
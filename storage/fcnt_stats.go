@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"github.com/jmoiron/sqlx"
+)
+
+// FCntStats define device frame counter statistics
+type FCntStats struct {
+	DeviceEUI   EUI64   `db:"device_eui" json:"device_eui"`
+	LastFCnt    *int64  `db:"last_fcnt" json:"last_fcnt"`
+	Received    int64   `db:"fcnt_received" json:"received"`     // 接收的非重复帧数
+	Lost        int64   `db:"fcnt_lost" json:"lost"`             // 帧计数跳变推算的丢包数
+	Duplicates  int64   `db:"fcnt_duplicates" json:"duplicates"` // 丢弃的重复帧数
+	Resets      int64   `db:"fcnt_resets" json:"resets"`         // 帧计数重置(设备重启)次数
+	LossPercent float64 `db:"-" json:"loss_percent"`             // 丢包率百分比
+}
+
+// IncFCntStats increase frame counter statistics of device
+func IncFCntStats(devEUI EUI64, received, lost, duplicates, resets int64) error {
+	_, err := db.Exec(`
+		update device set
+		fcnt_received=fcnt_received+$2,
+		fcnt_lost=fcnt_lost+$3,
+		fcnt_duplicates=fcnt_duplicates+$4,
+		fcnt_resets=fcnt_resets+$5
+		where device_eui=$1`,
+		devEUI,
+		received,
+		lost,
+		duplicates,
+		resets,
+	)
+	return err
+}
+
+// GetFCntStats get frame counter statistics of device
+func GetFCntStats(devEUI EUI64) (FCntStats, error) {
+	var stats FCntStats
+	err := sqlx.Get(db, &stats, `
+		select device_eui,
+		last_fcnt,
+		fcnt_received,
+		fcnt_lost,
+		fcnt_duplicates,
+		fcnt_resets
+		from device
+		where device_eui=$1`,
+		devEUI,
+	)
+	if err != nil {
+		return stats, err
+	}
+
+	if total := stats.Received + stats.Lost; total > 0 {
+		stats.LossPercent = float64(stats.Lost) * 100 / float64(total)
+	}
+	return stats, nil
+}
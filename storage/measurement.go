@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Measurement define decoded device measurement
+type Measurement struct {
+	DeviceEUI  EUI64     `db:"device_eui" json:"device_eui"`
+	Field      string    `db:"field" json:"field"`
+	Value      *float64  `db:"value" json:"value,omitempty"`
+	TextValue  *string   `db:"text_value" json:"text_value,omitempty"`
+	SampleTime time.Time `db:"sample_time" json:"sample_time"`
+	ReceivedAt time.Time `db:"received_at" json:"received_at"`
+}
+
+// MeasurementFilter filter of measurements query
+type MeasurementFilter struct {
+	Field string
+	From  *time.Time
+	To    *time.Time
+	Limit int
+}
+
+// CreateMeasurements create measurements in one transaction
+func CreateMeasurements(ms []Measurement) error {
+	if len(ms) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err = createMeasurements(tx, ms); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReplaceMeasurements replace device measurements of the uplink received at receivedAt
+func ReplaceMeasurements(devEUI EUI64, receivedAt time.Time, ms []Measurement) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		delete from measurement
+		where device_eui=$1 and received_at=$2`,
+		devEUI,
+		receivedAt,
+	)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err = createMeasurements(tx, ms); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func createMeasurements(tx *sql.Tx, ms []Measurement) error {
+	for _, m := range ms {
+		_, err := tx.Exec(`
+			insert into measurement (
+				device_eui,
+				field,
+				value,
+				text_value,
+				sample_time,
+				received_at
+			)values($1,$2,$3,$4,$5,$6)`,
+			m.DeviceEUI,
+			m.Field,
+			m.Value,
+			m.TextValue,
+			m.SampleTime,
+			m.ReceivedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetMeasurements get device measurements order by sample time desc
+func GetMeasurements(devEUI EUI64, filter MeasurementFilter) ([]Measurement, error) {
+	where := []string{"device_eui=$1"}
+	args := []interface{}{devEUI}
+	if filter.Field != "" {
+		args = append(args, filter.Field)
+		where = append(where, fmt.Sprintf("field=$%d", len(args)))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		where = append(where, fmt.Sprintf("sample_time>=$%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		where = append(where, fmt.Sprintf("sample_time<=$%d", len(args)))
+	}
+	args = append(args, filter.Limit)
+
+	ms := []Measurement{}
+	err := sqlx.Select(db, &ms, fmt.Sprintf(`
+		select device_eui,
+		field,
+		value,
+		text_value,
+		sample_time,
+		received_at
+		from measurement
+		where %s
+		order by sample_time desc, id desc
+		limit $%d`,
+		strings.Join(where, " and "),
+		len(args),
+	), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return ms, nil
+}
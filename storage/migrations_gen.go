@@ -2,6 +2,22 @@
 // sources:
 // ../migrate/001_create_device.sql
 // ../migrate/004_create_user.sql
+// ../migrate/005_create_measurement.sql
+// ../migrate/006_create_uplink_log.sql
+// ../migrate/007_add_uplink_log_application_id.sql
+// ../migrate/008_create_downlink.sql
+// ../migrate/009_add_downlink_acked_at.sql
+// ../migrate/010_create_rejected_frame.sql
+// ../migrate/011_create_alarm_event.sql
+// ../migrate/012_create_alarm.sql
+// ../migrate/013_add_device_liveness.sql
+// ../migrate/014_add_device_fcnt_stats.sql
+// ../migrate/015_add_device_metadata.sql
+// ../migrate/016_create_device_group.sql
+// ../migrate/017_add_device_order_index.sql
+// ../migrate/018_add_user_role.sql
+// ../migrate/019_add_user_state.sql
+// ../migrate/020_add_downlink_attempts.sql
 // DO NOT EDIT!
 
 package storage
@@ -109,6 +125,326 @@ func _004_create_userSql() (*asset, error) {
 	return a, nil
 }
 
+var __005_create_measurementSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x51\xbd\x6e\x32\x31\x10\xec\xef\x29\xb6\x04\x7d\x20\xa1\x4f\x4a\x45\x9b\x57\x48\x6d\x2d\xf6\x00\xab\xf8\xe7\xb4\xde\x3b\x20\x4f\x1f\xdd\x19\x92\x4b\x52\x44\x69\x2c\x5b\x33\x3b\x33\x9e\xdd\x6e\xe9\x5f\x92\x93\xb2\x81\x5e\xfa\xce\x2b\xa6\x9b\xf1\x21\x82\x12\xb8\x0e\x8a\x84\x6c\xab\x8e\x88\x48\x02\x1d\xe4\x54\xa1\xc2\x91\x7a\x95\xc4\x7a\xa3\x57\xdc\x36\x33\x1a\x30\x8a\x87\xc3\x20\x74\xb8\x19\x98\x72\x31\xca\x43\x8c\xa4\x38\x42\x91\x3d\xea\x9d\x44\x25\x53\x40\x84\x81\x3c\x57\xcf\x01\x4d\xe2\x28\x88\x81\x46\x56\x7f\x66\x5d\x3d\xed\xd6\x1f\x1a\x0d\x1f\x39\x0e\xa0\x50\x86\x29\x5e\xaf\xf0\x52\xa5\xe4\x86\x19\xae\xe6\x1a\xe1\x21\xf0\x7f\xb7\x5b\x37\xb0\x72\xea\x23\x9c\x49\x02\x4d\x47\x35\x4e\x3d\x5d\xc4\xce\xf3\x93\xde\x4a\xc6\x37\x2f\x85\x87\x8c\x08\x8e\xed\xf7\x91\x6e\xbd\xef\x1e\xe5\x49\x0e\xb8\x92\x84\xab\x5b\x14\xe8\xee\xed\xcc\x3f\x74\xcb\x38\x25\x7f\x29\xfa\xb3\xc5\x0d\xcd\xe4\xcd\x32\xfc\x64\xb3\x5c\xd9\x73\xb9\xe4\x2e\x68\xe9\xff\xe8\xba\x6f\x43\x3f\xf6\xbc\xef\xde\x07\x00\x03\x76\xba\xd0\x11\x02\x00\x00")
+
+func _005_create_measurementSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__005_create_measurementSql,
+		"005_create_measurement.sql",
+	)
+}
+
+func _005_create_measurementSql() (*asset, error) {
+	bytes, err := _005_create_measurementSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "005_create_measurement.sql", size: 529, mode: os.FileMode(436), modTime: time.Unix(1792302725, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __006_create_uplink_logSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x92\xc1\x6e\xf2\x30\x10\x84\xef\x7e\x8a\xbd\x41\xf4\x83\x84\x7e\xa9\xa7\x5c\xfb\x0a\x3d\x5b\x1b\x7b\x81\x2d\x8e\x6d\x6d\x36\x90\xb4\xea\xbb\x57\x40\x80\x14\x68\x7a\xb3\x35\xdf\x8c\xbd\x9a\x5d\x2e\xe1\x5f\xcd\x1b\x41\x25\x78\xcb\xc6\x09\x1d\x4f\x8a\x55\x20\x68\x73\xe0\xb8\xb3\x21\x6d\xe6\x06\x00\x80\x3d\x54\xbc\x69\x48\x18\x03\x64\xe1\x1a\xa5\x87\x1d\xf5\x8b\x93\xea\x69\xcf\x8e\x2c\xb5\x0c\x55\xaf\x84\x10\x93\x42\x6c\x43\x18\x64\x54\x7c\x2a\xac\xad\x8b\x7a\x0c\xe6\xa8\x57\x09\x3c\xad\xb1\x0d\x0a\xab\x0b\x94\x93\x28\x34\x35\x86\x30\xc5\x49\x67\x6b\x52\x3c\x3d\xf6\xde\xa4\x58\x3d\x92\xb3\xcf\xaf\xd9\x19\xce\x92\x34\xb9\x14\xac\xf6\x99\x60\x8f\xe2\xb6\x28\xf3\x97\x55\xf1\xc4\x34\x58\x3c\xb9\xe4\xc9\x36\x8a\xda\x36\x57\xcb\xff\x55\x71\x3f\xed\x99\x23\x91\x24\xa0\xd4\x3d\xf9\xf1\x25\x52\xc8\x11\xef\xc9\x5b\x54\x50\xae\xa9\x51\xac\x33\x1c\x58\xb7\xa7\x2b\x7c\xa4\x48\x57\xb7\x29\x4a\x73\x29\x89\xa3\xa7\x0e\xd8\x77\xf6\x56\x94\x1d\x5a\x18\x87\xa6\x38\x6e\xf2\x56\xd3\x02\x46\x54\x51\x4e\xc6\xfe\x9e\xf7\x33\xc3\x8c\xf7\xe9\x35\x1d\xa2\xf1\x92\xf2\x9f\x99\xe5\x04\xf6\x38\xd1\x40\xdf\x2f\x69\x69\xbe\x07\x00\xb0\xf2\x27\xa3\xcd\x02\x00\x00")
+
+func _006_create_uplink_logSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__006_create_uplink_logSql,
+		"006_create_uplink_log.sql",
+	)
+}
+
+func _006_create_uplink_logSql() (*asset, error) {
+	bytes, err := _006_create_uplink_logSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "006_create_uplink_log.sql", size: 717, mode: os.FileMode(436), modTime: time.Unix(1792302794, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __007_add_uplink_log_application_idSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\xcc\x31\x0e\xc2\x30\x0c\x05\xd0\x3d\xa7\xf8\x3b\xaa\xc4\xde\x95\x2b\x30\x57\x6e\x13\x22\x8b\x5f\xdb\x8a\x1c\x71\x7d\x56\x06\x7a\x81\xb7\x2c\xb8\x9d\xda\x87\x64\xc3\x33\x8a\x30\xdb\x40\xca\xce\x86\x19\x54\x7b\x6f\xf4\x0e\xa9\x15\x87\x73\x9e\x06\x89\xa0\x1e\x92\xea\xb6\x69\xc5\xae\x5d\x2d\x61\x9e\xb0\x49\xa2\xb6\x97\x4c\x26\xee\x6b\x29\xbf\xf6\xc3\x3f\x76\xa5\xd7\xe1\xf1\x9f\x5f\xcb\x77\x00\x77\x87\xec\x94\xa0\x00\x00\x00")
+
+func _007_add_uplink_log_application_idSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__007_add_uplink_log_application_idSql,
+		"007_add_uplink_log_application_id.sql",
+	)
+}
+
+func _007_add_uplink_log_application_idSql() (*asset, error) {
+	bytes, err := _007_add_uplink_log_application_idSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "007_add_uplink_log_application_id.sql", size: 160, mode: os.FileMode(436), modTime: time.Unix(1792302867, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __008_create_downlinkSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x92\xcd\x8e\xea\x30\x0c\x85\xf7\x7d\x0a\xef\x00\x5d\x90\xd0\xdd\x76\x7b\x5f\xe1\xae\x2b\x37\x71\xc1\x22\x71\x22\xc7\xe5\x67\x9e\x7e\x04\x2d\x6a\x07\x06\x46\xb3\x6b\xeb\xaf\x5f\x8e\x9c\xb3\xd9\xc0\x9f\xc8\x3b\x45\x23\xf8\x9f\x2b\xa7\x74\x7d\x32\x6c\x03\x81\x4f\x27\x09\x2c\x87\x65\x05\x00\xc0\x1e\x5a\xde\x15\x52\xc6\x00\x59\x39\xa2\x5e\xe0\x40\x97\xf5\x6d\xea\xe9\xc8\x8e\x1a\xea\x19\xda\x8b\x11\x82\x24\x03\xe9\x43\x00\xa5\x8e\x94\xc4\x51\x19\x21\x48\x02\x9e\x02\x19\x81\xc3\xe2\xd0\xd3\xa0\xc0\x9c\x03\x3b\x34\x4e\xd2\x0c\x87\xb1\xd8\xe4\xf1\xd4\x61\x1f\x0c\xb6\x03\xdd\x35\x39\xa9\x41\x89\x18\xc2\x9c\x1b\xa6\x2e\x49\xc7\x1a\xc9\x43\x9b\x52\x20\x94\x67\x4f\x87\xa1\x8c\x27\x7b\x34\x7c\x88\x7d\xd7\xc4\x88\xe2\xc1\xe8\xfc\x4d\x92\xc5\x62\xa0\x8a\xa1\xf5\x05\x8e\xa8\x6e\x8f\xba\xfc\xbb\x5d\x3d\x68\x48\x35\xe9\x0f\x92\x61\xf3\xbe\x41\x03\xe3\x48\xc5\x30\x66\x38\xb1\xed\x6f\xaf\xf0\x91\x84\x1e\xac\x7d\xf6\xbf\xfc\xa3\x90\xd8\x3b\xbc\x5a\xd5\xd5\xbd\x02\x2c\x9e\xce\xc0\xfe\xdc\xdc\x6b\xd0\x8c\x77\x3c\x4b\x9a\x64\x2a\xc9\xd4\x80\x35\x4c\xc8\xaa\x7e\x23\x1c\x17\x37\xb7\x0c\x9f\xae\x39\xe6\xcd\xfc\x97\x4e\x52\x79\x4d\xf9\xb5\xa5\x7e\x39\x7f\x8a\x3d\xa2\x5f\x4b\x5e\x57\x9f\x03\x00\x70\x42\xa5\x81\x0b\x03\x00\x00")
+
+func _008_create_downlinkSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__008_create_downlinkSql,
+		"008_create_downlink.sql",
+	)
+}
+
+func _008_create_downlinkSql() (*asset, error) {
+	bytes, err := _008_create_downlinkSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "008_create_downlink.sql", size: 779, mode: os.FileMode(436), modTime: time.Unix(1792303015, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __009_add_downlink_acked_atSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\xcc\x3d\x0a\x02\x41\x0c\x06\xd0\x7e\x4e\xf1\xf5\xb2\x27\xd8\xd6\x2b\x58\x4b\xdc\x04\x0d\x9b\x9f\x61\x8c\x0c\x78\x7a\xc1\x4a\x64\xcb\xd7\xbc\x65\xc1\xc9\xf5\x3e\xa8\x04\x97\xde\xc8\x4a\x06\x8a\x6e\x26\xe0\x9c\x61\x1a\x3b\x88\x19\x5b\xda\xcb\x03\xb4\xed\xc2\x57\x2a\x94\xba\x3c\x8b\xbc\x63\x6a\x3d\xbe\xc4\x3b\x43\xd6\xd6\x7e\xcb\x73\xce\x38\x4e\x79\x64\xff\x5f\xd7\xf6\x19\x00\x04\x3b\x7d\x69\x8f\x00\x00\x00")
+
+func _009_add_downlink_acked_atSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__009_add_downlink_acked_atSql,
+		"009_add_downlink_acked_at.sql",
+	)
+}
+
+func _009_add_downlink_acked_atSql() (*asset, error) {
+	bytes, err := _009_add_downlink_acked_atSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "009_add_downlink_acked_at.sql", size: 143, mode: os.FileMode(436), modTime: time.Unix(1792303955, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __010_create_rejected_frameSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x90\x41\x4e\xc3\x30\x10\x45\xf7\x3e\xc5\xdf\x35\x11\xa9\x54\xb1\xed\x96\x2b\xb0\x8e\x26\xf6\x4f\x6b\x70\xec\x68\x32\x69\x09\xa7\x47\xb4\x25\x14\xa9\x62\x67\x6b\x9e\x9e\xf4\xfe\x76\x8b\xa7\x21\x1e\x54\x8c\x78\x1d\x9d\x57\x7e\xbf\x4c\xba\x44\x28\xdf\xe8\x8d\xa1\xed\x55\x06\x56\x0e\x00\x02\x4f\xd1\xb3\xe5\x1c\xd1\x2d\x46\x41\x2e\x86\x3c\xa7\x04\x65\x4f\x65\xf6\x9c\x6e\x10\x4a\x46\x60\xa2\x11\x5e\x26\x2f\x81\xcd\x45\x31\x6a\xb1\xe2\x4b\x6a\x6d\x19\x89\x93\xa8\x3f\x8a\x56\xcf\xbb\x7a\x75\x5d\x39\xa5\x4c\x25\xff\x03\xf8\x32\x67\x43\x17\x0f\x31\xdb\x7a\x42\x60\x2f\x73\x32\xec\xae\x96\x24\x93\xb5\x54\x2d\x0a\xe3\xc7\x03\x6e\xb3\xb9\x03\xd7\x64\x31\x58\x1c\x38\x99\x0c\x23\xce\xd1\x8e\x97\x2f\x3e\x4b\xe6\xaa\xf8\xc9\x89\x83\xe8\x82\x77\x2e\xd5\xef\x3a\xcd\xdf\xcc\xe6\x56\x53\xbb\x7a\xef\xdc\xfd\xe8\x2f\xe5\x9c\x5d\xd0\x32\x3e\x1c\x7d\xef\xbe\x06\x00\xb9\xd9\xbd\x9c\xa1\x01\x00\x00")
+
+func _010_create_rejected_frameSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__010_create_rejected_frameSql,
+		"010_create_rejected_frame.sql",
+	)
+}
+
+func _010_create_rejected_frameSql() (*asset, error) {
+	bytes, err := _010_create_rejected_frameSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "010_create_rejected_frame.sql", size: 417, mode: os.FileMode(436), modTime: time.Unix(1792304120, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __011_create_alarm_eventSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x91\xbd\x4e\x03\x31\x10\x84\x7b\x3f\xc5\x96\x89\x48\x24\x84\x44\x95\x96\x57\xa0\xb6\xf6\xec\x21\x59\xe1\x3f\xed\x6d\x2e\x39\x9e\x1e\x5d\x2e\x90\x83\x14\xd0\x58\xb6\x66\x76\xbc\xfa\x66\xbb\xa5\x87\x2c\x7b\x65\x03\xbd\x36\x17\x14\xd3\xcd\xb8\x4b\x20\x4e\xac\xd9\x63\x40\xb1\x95\x23\x22\x92\x48\x9d\xec\x7b\xa8\x70\xa2\xa6\x92\x59\x47\x7a\xc7\xb8\xb9\xa8\x11\x83\x04\x78\x1c\x85\xba\xd1\xc0\x54\xaa\x51\x39\xa6\x44\x8a\x37\x28\x4a\x40\x7f\x35\x51\x2d\x14\x91\x60\xa0\xc0\x7d\xe0\x88\x39\xa2\x69\xb5\x1a\x6a\xf2\x36\x36\xd0\xc0\x1a\x0e\xac\xab\xa7\xc7\xf5\x77\xd6\xec\x2b\x9c\x6f\xf2\xf3\x9d\xdc\x73\x6e\x09\xde\x24\x83\xa6\xa3\x37\xce\x8d\x4e\x62\x87\xcb\x93\x3e\x6a\xc1\xaf\x11\x45\x80\x0c\x88\x9e\xed\xef\x11\xb7\xde\xb9\x2f\x54\x52\x22\xce\x24\xf1\xec\x17\xb8\xfc\x95\xc5\x72\x91\x5a\x7e\x00\xbd\xd1\xda\xd0\xc2\x36\x25\x2f\x3b\x79\xa9\xa7\xe2\xa2\xd6\xf6\xef\x8f\x76\xb3\xfd\xae\xc2\x9d\xfb\x1c\x00\x5e\x15\x88\xd3\xec\x01\x00\x00")
+
+func _011_create_alarm_eventSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__011_create_alarm_eventSql,
+		"011_create_alarm_event.sql",
+	)
+}
+
+func _011_create_alarm_eventSql() (*asset, error) {
+	bytes, err := _011_create_alarm_eventSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "011_create_alarm_event.sql", size: 492, mode: os.FileMode(436), modTime: time.Unix(1792304239, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __012_create_alarmSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x52\x41\x6e\xe3\x30\x0c\xbc\xfb\x15\xbc\x39\xc1\x26\xc0\xee\x02\x7b\xf2\xa2\xa7\x7e\xa1\x67\x81\x96\x98\x84\x88\x2c\xb9\x14\x95\xc4\x7d\x7d\x61\xc7\x71\x8c\x36\x75\xd1\x9b\x84\x19\x0e\x39\x1c\x6e\xb7\xf0\xab\xe1\xbd\xa0\x12\xbc\xb4\x85\x15\xea\x5f\x8a\xb5\x27\x40\x8f\xd2\xac\x0a\x00\x00\x76\x50\xf3\x3e\x91\x30\x7a\x68\x85\x1b\x94\x0e\x8e\xd4\x6d\x06\xd4\xd1\x89\x2d\x19\xca\x0c\x75\xa7\x84\x10\xa2\x42\xc8\xde\x83\xd0\x8e\x84\x82\xa5\x34\x92\x20\x06\x70\xe4\x49\x09\x2c\x26\x8b\x8e\xae\x12\xad\x44\x8d\x36\x7a\xa3\x5d\x4b\x70\x42\xb1\x07\x94\xd5\xdf\xdf\xeb\x49\xeb\xca\x0b\xd8\xdc\xe1\x7f\x9f\xe0\xa4\xa8\x39\x2d\xd4\xdb\x98\x83\xf6\x5e\x38\xe8\x04\x81\xa3\x1d\x66\xaf\xf0\xe7\xda\x44\x90\x13\x39\x83\x0a\xca\x0d\x25\xc5\xa6\x85\x33\xeb\x61\xf8\xc2\x5b\x0c\xf4\x41\xd5\x63\x52\xf3\xe3\x2a\xb4\xc7\x65\xfe\x9c\x56\x77\x0f\x6d\x4f\xa3\x97\xe5\x68\xd0\x13\xca\xb2\x6c\xb1\xae\x8a\x5b\xd2\x39\xf0\x6b\x26\xe0\xe0\xe8\x02\xec\x2e\x66\x08\xdd\x8c\x89\xf6\xdb\x36\x68\x95\x4f\x43\x70\x03\xb6\xba\xa7\xbd\x81\x9e\xb0\x86\xf3\x81\x84\xc6\xdd\xff\x7f\x2a\xc7\x11\xca\xea\xd6\xe4\x0b\xf5\xfb\xc2\x1e\x6a\x4f\x70\x3f\xee\xfc\x4e\x9f\xe3\x39\x14\x4e\x62\xfb\xad\x70\xb5\x48\x9b\xb9\x1b\x89\xb3\xbb\xaf\x8a\xf7\x01\x00\xdf\x2a\x7e\xde\x1b\x03\x00\x00")
+
+func _012_create_alarmSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__012_create_alarmSql,
+		"012_create_alarm.sql",
+	)
+}
+
+func _012_create_alarmSql() (*asset, error) {
+	bytes, err := _012_create_alarmSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "012_create_alarm.sql", size: 795, mode: os.FileMode(436), modTime: time.Unix(1792304315, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __013_add_device_livenessSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x92\x41\x6b\xf3\x30\x0c\x86\xef\xfe\x15\xba\xb5\xe5\xfb\x0a\x65\xd7\x5c\xf7\x17\x76\x36\xaa\xad\xa6\x62\x8e\x1c\x64\x39\x29\xfb\xf5\x23\xcd\x06\x6b\x37\x88\x6f\x09\x7e\xf4\xe0\xf7\x95\x8f\x47\xf8\x37\x70\xaf\x68\x04\x6f\xa3\xc3\x64\xa4\x60\x78\x4e\x04\x91\x26\x0e\x04\x18\x23\x84\x9c\xea\x20\xa0\x34\x66\x35\xcf\x62\xa4\x13\x26\x58\x3e\x7a\x52\x90\x6c\x20\x35\x25\x88\x74\xc1\x9a\x0c\x4e\xdd\x86\xa9\x18\x5a\x2d\x30\xa1\x86\x2b\xea\xfe\xe5\x74\xf8\x2d\xd9\x55\x79\x97\x3c\xcb\x6e\x4b\x96\xb0\x98\x2f\x44\xe2\xd1\xc0\x78\xa0\x62\x38\x8c\x30\xb3\x5d\xef\xbf\xf0\x91\x85\x9a\x24\x97\x20\x06\x67\xee\x59\xac\x89\xd7\x52\xf8\xbb\x85\xa6\x81\x22\x0a\x31\xd7\x85\x18\x95\x02\x17\xce\xd2\x39\x17\x94\x96\x05\xb0\x44\xba\x01\xc7\x9b\x5f\xc7\xfd\x5a\x93\x7f\x08\x98\xe5\x4b\xbe\x5f\x4f\xff\x3f\xe4\x3f\x74\xce\xfd\xdc\xe9\x6b\x9e\xc5\x45\xcd\x63\x93\xfc\xcf\x0c\xf7\xe9\xa7\x10\x6d\xe0\x52\x4f\x1b\xb9\x14\xdf\x46\xb6\xde\x74\x4d\xb7\x89\x3d\x3d\xe9\xce\x7d\x0e\x00\xe7\x79\x33\x00\x11\x03\x00\x00")
+
+func _013_add_device_livenessSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__013_add_device_livenessSql,
+		"013_add_device_liveness.sql",
+	)
+}
+
+func _013_add_device_livenessSql() (*asset, error) {
+	bytes, err := _013_add_device_livenessSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "013_add_device_liveness.sql", size: 785, mode: os.FileMode(436), modTime: time.Unix(1792304425, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __014_add_device_fcnt_statsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\xd0\x31\xaa\xc3\x30\x10\x84\xe1\xde\xa7\x98\xfe\x61\x78\xbd\xdb\x5c\x21\x75\x90\xb5\x63\xb3\xb0\x96\x8c\xbc\x52\xae\x1f\x52\x25\x85\x09\x02\xf7\x3f\x1f\xcc\x8c\x23\xfe\x36\x5d\x4b\x70\xe2\xbe\x0f\xc1\x9c\x05\x1e\x66\x23\x84\x4d\x23\x11\x44\x10\xb3\xd5\x2d\x61\x89\xc9\x1f\x85\x91\xda\x28\x98\x75\xd5\xe4\x48\xd9\x91\xaa\x19\x84\x4b\xa8\xe6\xf8\x9f\x7a\x18\xcb\x87\x5f\x24\xa4\xee\xa6\x31\x38\x8f\x8b\x50\xe1\x41\xff\x89\x0c\xdf\x3f\xdd\xf2\x33\x9d\xb1\x52\xf2\x7e\xe2\x4e\x5d\xed\x67\x4c\x5f\xff\xfe\xaf\xaf\x2c\x8c\xd4\x46\x99\x86\xd7\x00\xa2\xaf\x18\x33\xed\x01\x00\x00")
+
+func _014_add_device_fcnt_statsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__014_add_device_fcnt_statsSql,
+		"014_add_device_fcnt_stats.sql",
+	)
+}
+
+func _014_add_device_fcnt_statsSql() (*asset, error) {
+	bytes, err := _014_add_device_fcnt_statsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "014_add_device_fcnt_stats.sql", size: 493, mode: os.FileMode(436), modTime: time.Unix(1792304491, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __015_add_device_metadataSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x93\xc1\x4e\xc3\x30\x0c\x86\xef\x79\x0a\xdf\xb6\x09\x4d\x1a\xe7\x5e\x79\x05\x4e\x08\x4d\x6e\x62\x8a\x51\xea\x44\xa9\x3b\x26\x21\xde\x1d\xa5\x05\xba\x8d\xb6\x91\x38\x56\xfe\xf2\xe5\xb7\x9d\xee\xf7\x70\xd7\x72\x93\x50\x09\x1e\xa3\x41\xaf\x94\x40\xb1\xf6\x04\x8e\x4e\x6c\x09\xd0\x39\xb0\xc1\xf7\xad\x80\x60\x4b\x70\xc2\x64\x5f\x31\x6d\xef\x0f\x87\x1d\x48\x50\x90\xde\x7b\x70\xf4\x82\xbd\x57\xd8\x6c\xaa\x82\xc4\x51\x67\x13\x47\xe5\x20\xa0\x74\xd6\xff\x38\x30\x46\xcf\x16\xb3\xe3\xc8\x0e\x6a\x6e\x58\x66\x44\x87\x92\x47\xb1\xe9\x86\x10\x4f\xcf\x33\x31\x3e\x3e\x8b\x41\x7c\x18\x53\x40\x0c\x2c\x5a\xa2\x51\x35\x71\xdd\x2b\x75\xf0\xd6\x05\xa9\x97\xee\x34\x36\x51\x5e\x08\x8b\xa3\x33\xb0\x3b\x1f\x47\xd7\x71\x58\x40\x90\x6f\xf5\x36\x7f\xee\xaa\x45\xfa\x66\x4a\xd3\xb9\xeb\xc2\x8a\x61\x98\xcf\xef\x39\xe8\x3b\x96\x06\x1a\x96\x6d\x2e\xac\xdd\x3c\x35\x3a\x77\x7a\x2a\xef\x2a\x63\x2e\x9f\xe0\x43\x78\x17\xe3\x52\x88\x6b\xce\x6a\x81\xc8\xa1\x96\x6a\xd7\x2d\x2f\x51\x79\xa0\xb3\x4b\x1c\xf0\x3f\x5b\x2c\xa2\x3f\xcf\xa3\x08\x8e\xd1\x0b\xd0\x6d\x0f\x05\xfc\xe2\x47\x2b\xb2\x82\x2d\x55\xe6\x6b\x00\x52\x9c\x44\x10\x0d\x04\x00\x00")
+
+func _015_add_device_metadataSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__015_add_device_metadataSql,
+		"015_add_device_metadata.sql",
+	)
+}
+
+func _015_add_device_metadataSql() (*asset, error) {
+	bytes, err := _015_add_device_metadataSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "015_add_device_metadata.sql", size: 1037, mode: os.FileMode(436), modTime: time.Unix(1792304543, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __016_create_device_groupSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x93\xdf\x6e\x1a\x3d\x10\xc5\xef\xf7\x29\xce\x05\x12\xbb\x0a\xe1\x23\xd7\x28\x37\x9f\xda\x27\xa8\x7a\xbd\x9a\xac\x07\xe3\xd6\x6b\x6f\xc6\xe3\xfc\xe9\xd3\x57\x5e\x03\x01\x9a\x44\xad\xb8\x01\x3c\x3e\x33\xf3\x3b\xc7\xb7\xb7\xb8\x19\x9d\x15\x52\xc6\xf7\xa9\x19\x84\xcb\x37\xa5\x07\xcf\x30\xfc\xe4\x06\xee\xad\xc4\x3c\xb5\x0d\x00\x38\x83\x07\x67\x13\x8b\x23\x8f\x49\xdc\x48\xf2\x8a\x9f\xfc\xba\x9a\x4f\x27\x12\x0e\xda\xd7\x22\x17\x14\xc2\x3b\x16\x0e\x03\xa7\x0b\x2d\xc4\x00\xc3\x9e\x95\x21\x9c\x54\xdc\xa0\x55\x20\xd0\xc8\x78\x22\x19\xf6\x24\xed\xdd\x66\xd3\x21\x44\x45\xc8\xde\xd7\x73\xc3\x69\x10\x37\xa9\x8b\x01\xca\x2f\x7a\x3a\x86\xe1\x1d\x65\xaf\x58\x2e\x6b\x65\xdd\xc3\xf4\xa4\x50\x37\x72\x52\x1a\x27\x3c\x3b\xdd\xcf\x3f\xf1\x2b\x06\xbe\xd2\xce\x93\xf9\xeb\x1b\x4d\xb7\x6d\x8e\xa8\x72\x70\x8f\x99\xe1\x82\xe1\x17\x38\xf3\xd2\x9f\x6f\xda\x1f\x90\xcc\x8b\xc5\x70\x41\xa1\x1d\x22\x79\x4e\x03\xb7\x27\x6e\x2b\x6c\xba\x15\x4a\x71\x69\x40\x5e\x59\x2e\xac\x00\x19\x83\x21\xfa\x3c\x06\xcc\x1a\xff\xc4\x3a\x71\x65\xb9\x3d\x8e\xfe\xc1\xcc\xce\xbc\x8d\xda\x1e\xff\x2a\x03\x9d\x67\xe5\x9b\x92\xf2\xc8\x41\xff\x67\xeb\xc2\x51\x71\x97\xc3\x30\xbb\x73\x21\x38\x91\xee\xdb\xab\x79\x3b\x08\x6b\x96\x90\xaa\x91\x94\xb0\x58\xcc\x3e\xcc\xc8\x85\x87\x2c\xc9\x3d\x31\xa8\xa4\x47\xa3\xb4\xce\xac\x70\x06\xaa\x40\x5a\xc1\xf0\xa4\xfb\x0e\x94\x50\xf3\x59\x3e\x89\x3d\x0f\x8a\x77\xeb\x37\xd8\x49\x1c\x2f\x01\x3d\xef\x59\xb8\x24\xfb\xfe\xc4\xf4\xa4\x95\x43\x59\x86\xbc\xbf\x56\xb7\xeb\xa2\x6f\xd7\x67\x1d\xec\xba\xce\x44\xeb\x79\x2a\xdc\xe0\xee\x74\xeb\xcf\xae\x16\x3f\xa2\x0b\xa7\xf5\x40\x85\x79\x51\xc5\x3d\xe8\x4d\x76\x56\xe8\x9a\xb3\xce\xe5\xbd\x04\xdb\x93\xb5\x6d\xed\xb7\xfc\x6f\x89\x28\x86\x05\x0f\xaf\x15\xc8\xfc\x4e\xba\xba\xea\xb1\x41\xb3\x58\xc0\x53\xb0\x99\x2c\x23\x3d\x7a\xa4\x39\x58\xdb\xf7\x4d\xfd\x1a\xcc\xa5\xdd\x5f\xe2\x73\x68\x8c\xc4\xe9\x33\x8b\x0f\xce\x6e\x6b\xe1\x87\xe9\xda\xbe\x97\xec\xf9\xca\x55\xb4\x3f\x17\x3a\x30\x2a\x10\x0e\x85\xe7\x82\xbd\x95\x98\xa7\x6d\xf3\x7b\x00\xb6\xd0\x62\x43\xe0\x04\x00\x00")
+
+func _016_create_device_groupSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__016_create_device_groupSql,
+		"016_create_device_group.sql",
+	)
+}
+
+func _016_create_device_groupSql() (*asset, error) {
+	bytes, err := _016_create_device_groupSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "016_create_device_group.sql", size: 1248, mode: os.FileMode(436), modTime: time.Unix(1792304756, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __017_add_device_order_indexSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x90\xc1\x0a\x02\x21\x10\x86\xef\x3e\x85\xc7\x95\x76\xa1\xbb\xd7\x5e\xa1\xf3\x20\x3a\x84\xb0\xeb\x88\x33\x5b\xdb\xdb\x47\x18\xac\x05\x56\x37\xf9\x66\xfe\xcf\xe1\x9f\x26\x7d\x58\xe2\xa5\x38\x41\x7d\xce\xca\x17\x7c\xbe\x62\x0a\xb8\xe9\x18\x36\x08\x78\x8d\x1e\xa1\xf2\x00\x4e\x34\x25\x5d\xe1\xb0\xc3\xf1\x85\x00\xd7\x68\x6c\xd7\x32\x3b\x16\x60\xc4\xf4\xe1\x21\x37\x23\x7b\x1c\xda\xf9\xa8\x85\x40\xe2\x82\x2c\x6e\xc9\xc3\xd1\x98\x3f\x3f\x61\x71\xb2\x72\xa3\xaf\xe0\x4b\x22\x17\x12\xf2\x34\x83\xdc\x33\x36\xc1\x37\x6e\xac\x52\x6d\x59\x27\xba\x25\x15\x0a\xe5\x1f\x3e\xdb\x59\xaa\x57\xf5\xa6\x6d\x13\xbd\x9d\xbd\x7e\xab\x1e\x03\x00\x2e\xbf\xc2\x5a\xc7\x01\x00\x00")
+
+func _017_add_device_order_indexSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__017_add_device_order_indexSql,
+		"017_add_device_order_index.sql",
+	)
+}
+
+func _017_add_device_order_indexSql() (*asset, error) {
+	bytes, err := _017_add_device_order_indexSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "017_add_device_order_index.sql", size: 455, mode: os.FileMode(436), modTime: time.Unix(1792304894, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __018_add_user_roleSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\x90\xc1\x4e\xc3\x30\x10\x44\xef\xfe\x8a\xb9\x05\x04\x91\x10\xd7\x88\x1b\xbf\xc0\x19\x6d\xe3\x69\x1b\x61\x7b\xad\xf5\x3a\x11\x7f\x8f\x9a\x56\xa8\x07\x38\xee\x68\xe7\xed\xea\x8d\x23\x9e\xf2\x72\x32\x71\xe2\xa3\x06\x49\x4e\x83\xcb\x21\x11\xbd\xd1\x1a\x24\x46\xcc\x9a\x7a\x2e\x30\x4d\xc4\x2a\x36\x9f\xc5\x1e\x5e\x5f\x1e\x51\xd4\x51\x7a\x4a\x88\x3c\x4a\x4f\x8e\x61\x5d\xb8\xd1\x86\x29\x8c\xe3\xad\x3f\x1b\xc5\x19\x71\xe0\x51\x8d\x3b\xa3\xe1\x8b\xac\x88\x5c\x97\x99\xc8\x52\xe4\xc4\xcc\xe2\xcf\xd0\x92\xbe\x21\x31\x2f\xe5\x16\xb7\x2b\x25\xf4\x1a\x2f\x1f\xee\x03\x1a\x7d\xe7\xbc\x0d\x5a\x69\xe2\x7a\x39\xf8\xcf\xc6\x0e\x1b\xb0\x9d\x69\xd7\xfa\x67\x91\xfc\x9b\x4f\x21\xdc\x0b\x78\xd7\xad\xfc\xa1\x20\x9a\xd6\x7b\x07\x53\xf8\x19\x00\x91\x4a\xb2\x8d\x36\x01\x00\x00")
+
+func _018_add_user_roleSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__018_add_user_roleSql,
+		"018_add_user_role.sql",
+	)
+}
+
+func _018_add_user_roleSql() (*asset, error) {
+	bytes, err := _018_add_user_roleSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "018_add_user_role.sql", size: 310, mode: os.FileMode(436), modTime: time.Unix(1792304943, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __019_add_user_stateSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x93\xcd\x6a\xdb\x50\x10\x85\xf7\x7a\x8a\x59\x18\xdc\x52\x0c\x69\x16\x09\x41\x78\xd3\x84\xfe\x60\xaf\x4a\x43\xa1\x1b\x31\xd6\x8c\xa4\x4b\x46\x73\x95\x3b\x73\x2b\xc8\xd3\x17\x5b\xd4\x34\xd8\x58\xd0\x2c\x85\x3e\x1d\xbe\x39\x1c\xad\x56\xf0\xa1\x0f\x6d\x42\x67\x78\x1c\x0a\x14\xe7\x04\x8e\x3b\x61\xc8\xc6\xc9\x00\x89\xa0\x8e\x92\x7b\x05\x0a\xb6\x7f\x41\xb0\x8b\x51\x18\x15\x34\x3a\x68\x16\x01\xe2\x06\xb3\x38\x34\x28\xc6\xe5\xe5\x94\x06\x83\x30\x55\x12\xdb\xa0\x06\x41\x9d\x5b\x4e\xa7\x51\x57\x33\x31\x12\xeb\x27\xa6\x2a\xab\x07\x01\x0f\x3d\x9b\x63\x3f\xc0\x18\xbc\x3b\x3c\xc2\x4b\xd4\x39\x15\x41\xf3\x49\xa4\x42\xff\xdf\x90\x3e\x9b\x57\x75\x87\xda\x72\x35\xa0\xd9\x18\xd3\x1b\x1b\xaa\x13\xa3\x33\x5d\x72\x3a\x0d\xd6\x38\xbe\x7b\x5f\x16\xc5\x6a\x05\xc6\x4c\x4c\x80\xd4\x07\x05\xf3\x20\x02\xd9\x82\xb6\xe0\x1d\x1f\xf9\xa3\xea\xde\x1f\x26\x7f\x08\x0e\x51\xa1\x09\xc9\x1c\x0e\xbd\x14\x79\xa0\xfd\x34\x26\x47\x63\x3f\x7b\xee\xda\x53\xe6\x62\xec\x38\x4d\x64\xa5\xd8\xf3\x7a\x79\x10\x58\x02\x2a\xc1\x5f\xb2\xea\xd0\xba\xf5\x72\x71\x8d\x8b\x8f\x57\x8b\xfa\x7e\xfb\x85\xea\xbb\xd4\xeb\xf8\xe3\x69\x43\xfc\xfd\x66\x3b\x7c\x65\x0d\xcf\xc3\xf5\xaf\xdf\xdf\xee\x9e\x6f\x9a\x9f\xf7\xb7\xdb\x07\xdc\x3c\xbe\xdc\xd2\xe7\x5a\xd3\xe6\x13\x85\xe5\x74\xe4\x71\xb5\x0f\x71\xd4\x33\x7d\x52\x8a\xc3\x69\xa1\xe5\x0c\x79\xee\xba\xb9\x6f\x5e\xad\x68\x16\xfe\x67\xb6\x73\xec\xab\x3f\x65\x0e\xa6\x60\xb8\x13\xa6\xb2\xf8\x33\x00\x0a\xd0\x92\x1b\xd3\x03\x00\x00")
+
+func _019_add_user_stateSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__019_add_user_stateSql,
+		"019_add_user_state.sql",
+	)
+}
+
+func _019_add_user_stateSql() (*asset, error) {
+	bytes, err := _019_add_user_stateSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "019_add_user_state.sql", size: 979, mode: os.FileMode(436), modTime: time.Unix(1792305004, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var __020_add_downlink_attemptsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\xce\xb1\xca\x02\x31\x10\xc4\xf1\x3e\x4f\x31\xfd\xc7\xc1\xd7\xa7\xf5\x15\xac\x65\x35\xeb\x19\xdc\x6c\xc2\xde\x1c\x87\x3e\xbd\x60\x25\x22\x5c\x39\x30\xfc\xf8\x4f\x13\xfe\x5a\x9d\x43\xa8\x38\x8e\x24\x46\x0d\x50\xce\xa6\x28\x7d\x73\xab\x7e\x87\x94\x82\x4b\xb7\xb5\x39\x84\xd4\x36\xb8\xa0\x3a\x75\xd6\x80\x77\xc2\x57\x33\x14\xbd\xca\x6a\xc4\x7f\xde\x45\x42\x19\x8f\x93\x10\xac\x4d\x17\x4a\x1b\xd8\x2a\x6f\xef\x89\x67\x77\xcd\x29\x7d\x76\x1d\xfa\xe6\xbf\xd1\x12\x7d\x7c\xab\x79\xff\x2a\xa4\xb6\xc1\x25\xa7\xd7\x00\xf0\xa7\x05\x9e\xff\x00\x00\x00")
+
+func _020_add_downlink_attemptsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__020_add_downlink_attemptsSql,
+		"020_add_downlink_attempts.sql",
+	)
+}
+
+func _020_add_downlink_attemptsSql() (*asset, error) {
+	bytes, err := _020_add_downlink_attemptsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "020_add_downlink_attempts.sql", size: 255, mode: os.FileMode(436), modTime: time.Unix(1792305672, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -163,6 +499,22 @@ func AssetNames() []string {
 var _bindata = map[string]func() (*asset, error){
 	"001_create_device.sql": _001_create_deviceSql,
 	"004_create_user.sql": _004_create_userSql,
+	"005_create_measurement.sql": _005_create_measurementSql,
+	"006_create_uplink_log.sql": _006_create_uplink_logSql,
+	"007_add_uplink_log_application_id.sql": _007_add_uplink_log_application_idSql,
+	"008_create_downlink.sql": _008_create_downlinkSql,
+	"009_add_downlink_acked_at.sql": _009_add_downlink_acked_atSql,
+	"010_create_rejected_frame.sql": _010_create_rejected_frameSql,
+	"011_create_alarm_event.sql": _011_create_alarm_eventSql,
+	"012_create_alarm.sql": _012_create_alarmSql,
+	"013_add_device_liveness.sql": _013_add_device_livenessSql,
+	"014_add_device_fcnt_stats.sql": _014_add_device_fcnt_statsSql,
+	"015_add_device_metadata.sql": _015_add_device_metadataSql,
+	"016_create_device_group.sql": _016_create_device_groupSql,
+	"017_add_device_order_index.sql": _017_add_device_order_indexSql,
+	"018_add_user_role.sql": _018_add_user_roleSql,
+	"019_add_user_state.sql": _019_add_user_stateSql,
+	"020_add_downlink_attempts.sql": _020_add_downlink_attemptsSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -207,6 +559,22 @@ type bintree struct {
 var _bintree = &bintree{nil, map[string]*bintree{
 	"001_create_device.sql": &bintree{_001_create_deviceSql, map[string]*bintree{}},
 	"004_create_user.sql": &bintree{_004_create_userSql, map[string]*bintree{}},
+	"005_create_measurement.sql": &bintree{_005_create_measurementSql, map[string]*bintree{}},
+	"006_create_uplink_log.sql": &bintree{_006_create_uplink_logSql, map[string]*bintree{}},
+	"007_add_uplink_log_application_id.sql": &bintree{_007_add_uplink_log_application_idSql, map[string]*bintree{}},
+	"008_create_downlink.sql": &bintree{_008_create_downlinkSql, map[string]*bintree{}},
+	"009_add_downlink_acked_at.sql": &bintree{_009_add_downlink_acked_atSql, map[string]*bintree{}},
+	"010_create_rejected_frame.sql": &bintree{_010_create_rejected_frameSql, map[string]*bintree{}},
+	"011_create_alarm_event.sql": &bintree{_011_create_alarm_eventSql, map[string]*bintree{}},
+	"012_create_alarm.sql": &bintree{_012_create_alarmSql, map[string]*bintree{}},
+	"013_add_device_liveness.sql": &bintree{_013_add_device_livenessSql, map[string]*bintree{}},
+	"014_add_device_fcnt_stats.sql": &bintree{_014_add_device_fcnt_statsSql, map[string]*bintree{}},
+	"015_add_device_metadata.sql": &bintree{_015_add_device_metadataSql, map[string]*bintree{}},
+	"016_create_device_group.sql": &bintree{_016_create_device_groupSql, map[string]*bintree{}},
+	"017_add_device_order_index.sql": &bintree{_017_add_device_order_indexSql, map[string]*bintree{}},
+	"018_add_user_role.sql": &bintree{_018_add_user_roleSql, map[string]*bintree{}},
+	"019_add_user_state.sql": &bintree{_019_add_user_stateSql, map[string]*bintree{}},
+	"020_add_downlink_attempts.sql": &bintree{_020_add_downlink_attemptsSql, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// RejectedFrame define counter of frames rejected by validation
+type RejectedFrame struct {
+	DeviceEUI      *EUI64    `db:"device_eui" json:"device_eui,omitempty"`
+	ProtocolType   string    `db:"protocol_type" json:"protocol_type"`
+	Reason         string    `db:"reason" json:"reason"`
+	Count          int64     `db:"count" json:"count"`
+	LastError      string    `db:"last_error" json:"last_error,omitempty"`
+	LastRejectedAt time.Time `db:"last_rejected_at" json:"last_rejected_at"`
+}
+
+// RejectedFrameFilter filter of rejected frame counters query
+type RejectedFrameFilter struct {
+	DeviceEUI    *EUI64
+	ProtocolType string
+}
+
+// IncRejectedFrame increase rejected frame counter of device, protocol and reason
+func IncRejectedFrame(devEUI EUI64, protocolType, reason, errText string) error {
+	_, err := db.Exec(`
+		insert into rejected_frame (
+			device_eui,
+			protocol_type,
+			reason,
+			count,
+			last_error,
+			last_rejected_at
+		)values($1,$2,$3,1,$4,$5)
+		on conflict (device_eui, protocol_type, reason) do update set
+		count=rejected_frame.count+1,
+		last_error=excluded.last_error,
+		last_rejected_at=excluded.last_rejected_at`,
+		devEUI,
+		protocolType,
+		reason,
+		errText,
+		time.Now(),
+	)
+	return err
+}
+
+// GetRejectedFrames get rejected frame counters per device, protocol and reason
+func GetRejectedFrames(filter RejectedFrameFilter) ([]RejectedFrame, error) {
+	where, args := rejectedFrameWhere(filter)
+	rfs := []RejectedFrame{}
+	err := sqlx.Select(db, &rfs, `
+		select device_eui,
+		protocol_type,
+		reason,
+		count,
+		last_error,
+		last_rejected_at
+		from rejected_frame
+		where `+where+`
+		order by last_rejected_at desc`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rfs, nil
+}
+
+// GetRejectedFramesByProtocol get rejected frame counters summed per protocol and reason
+func GetRejectedFramesByProtocol(filter RejectedFrameFilter) ([]RejectedFrame, error) {
+	where, args := rejectedFrameWhere(filter)
+	rfs := []RejectedFrame{}
+	err := sqlx.Select(db, &rfs, `
+		select protocol_type,
+		reason,
+		sum(count) as count,
+		max(last_rejected_at) as last_rejected_at
+		from rejected_frame
+		where `+where+`
+		group by protocol_type, reason
+		order by protocol_type, reason`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rfs, nil
+}
+
+func rejectedFrameWhere(filter RejectedFrameFilter) (string, []interface{}) {
+	where := []string{"true"}
+	var args []interface{}
+	if filter.DeviceEUI != nil {
+		args = append(args, *filter.DeviceEUI)
+		where = append(where, fmt.Sprintf("device_eui=$%d", len(args)))
+	}
+	if filter.ProtocolType != "" {
+		args = append(args, filter.ProtocolType)
+		where = append(where, fmt.Sprintf("protocol_type=$%d", len(args)))
+	}
+	return strings.Join(where, " and "), args
+}
@@ -0,0 +1,201 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// DecodeStatusSuccess payload decoded
+	DecodeStatusSuccess = "success"
+	// DecodeStatusFailed payload decode error
+	DecodeStatusFailed = "failed"
+	// DecodeStatusUnknownDevice device not registered
+	DecodeStatusUnknownDevice = "unknown_device"
+	// DecodeStatusNoDecoder protocol without decoder
+	DecodeStatusNoDecoder = "no_decoder"
+	// DecodeStatusDuplicate duplicate frame counter within dedup window, dropped
+	DecodeStatusDuplicate = "duplicate"
+)
+
+// UplinkLog define received uplink frame
+type UplinkLog struct {
+	ID            int64           `db:"id" json:"id"`
+	DeviceEUI     EUI64           `db:"device_eui" json:"device_eui"`
+	ApplicationID int64           `db:"application_id" json:"application_id"`
+	Data          HexBytes        `db:"data" json:"data"`
+	FCnt          uint32          `db:"f_cnt" json:"fcnt"`
+	FPort         uint8           `db:"f_port" json:"fport"`
+	RxMetadata    json.RawMessage `db:"rx_metadata" json:"rx_metadata"`
+	ProtocolType  string          `db:"protocol_type" json:"protocol_type"`
+	DecodeStatus  string          `db:"decode_status" json:"decode_status"`
+	DecodeError   string          `db:"decode_error" json:"decode_error"`
+	ReceivedAt    time.Time       `db:"received_at" json:"received_at"`
+}
+
+// UplinkLogFilter filter of uplink logs query
+type UplinkLogFilter struct {
+	DecodeStatus string
+	Limit        int
+}
+
+// CreateUplinkLog create uplink log
+func CreateUplinkLog(l UplinkLog) error {
+	rxMetadata := string(l.RxMetadata)
+	if rxMetadata == "" {
+		rxMetadata = "{}"
+	}
+	_, err := db.Exec(`
+		insert into uplink_log (
+			device_eui,
+			application_id,
+			data,
+			f_cnt,
+			f_port,
+			rx_metadata,
+			protocol_type,
+			decode_status,
+			decode_error,
+			received_at
+		)values($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)`,
+		l.DeviceEUI,
+		l.ApplicationID,
+		l.Data,
+		l.FCnt,
+		l.FPort,
+		rxMetadata,
+		l.ProtocolType,
+		l.DecodeStatus,
+		l.DecodeError,
+		l.ReceivedAt,
+	)
+	return err
+}
+
+// GetUplinkLogs get device recent uplink logs
+func GetUplinkLogs(devEUI EUI64, filter UplinkLogFilter) ([]UplinkLog, error) {
+	args := []interface{}{devEUI}
+	where := "device_eui=$1"
+	if filter.DecodeStatus != "" {
+		args = append(args, filter.DecodeStatus)
+		where += fmt.Sprintf(" and decode_status=$%d", len(args))
+	}
+	args = append(args, filter.Limit)
+
+	logs := []UplinkLog{}
+	err := sqlx.Select(db, &logs, fmt.Sprintf(`
+		select id,
+		device_eui,
+		application_id,
+		data,
+		f_cnt,
+		f_port,
+		rx_metadata,
+		protocol_type,
+		decode_status,
+		decode_error,
+		received_at
+		from uplink_log
+		where %s
+		order by received_at desc, id desc
+		limit $%d`,
+		where,
+		len(args),
+	), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+// DeleteUplinkLogsBefore delete uplink logs received before t
+func DeleteUplinkLogsBefore(t time.Time) (int64, error) {
+	res, err := db.Exec(`
+		delete from uplink_log
+		where received_at<$1`,
+		t,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// GetUplinkLogsInRange get uplink logs order by received time,
+// devEUI, from and to are optional, duplicates are skipped
+func GetUplinkLogsInRange(devEUI *EUI64, from, to *time.Time, limit int) ([]UplinkLog, error) {
+	where := []string{"decode_status<>$1"}
+	args := []interface{}{DecodeStatusDuplicate}
+	if devEUI != nil {
+		args = append(args, *devEUI)
+		where = append(where, fmt.Sprintf("device_eui=$%d", len(args)))
+	}
+	if from != nil {
+		args = append(args, *from)
+		where = append(where, fmt.Sprintf("received_at>=$%d", len(args)))
+	}
+	if to != nil {
+		args = append(args, *to)
+		where = append(where, fmt.Sprintf("received_at<=$%d", len(args)))
+	}
+	args = append(args, limit)
+
+	logs := []UplinkLog{}
+	err := sqlx.Select(db, &logs, fmt.Sprintf(`
+		select id,
+		device_eui,
+		application_id,
+		data,
+		f_cnt,
+		f_port,
+		rx_metadata,
+		protocol_type,
+		decode_status,
+		decode_error,
+		received_at
+		from uplink_log
+		where %s
+		order by received_at, id
+		limit $%d`,
+		strings.Join(where, " and "),
+		len(args),
+	), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return logs, nil
+}
+
+// UpdateUplinkLogDecode update decode result of uplink log
+func UpdateUplinkLogDecode(id int64, status, decodeError string) error {
+	_, err := db.Exec(`
+		update uplink_log set
+		decode_status=$2,
+		decode_error=$3
+		where id=$1`,
+		id,
+		status,
+		decodeError,
+	)
+	return err
+}
+
+// GetLastApplicationID get application id of device last uplink
+func GetLastApplicationID(devEUI EUI64) (int64, error) {
+	var id int64
+	err := sqlx.Get(db, &id, `
+		select application_id
+		from uplink_log
+		where device_eui=$1 and application_id>0
+		order by received_at desc
+		limit 1`,
+		devEUI,
+	)
+	return id, err
+}
@@ -1,20 +1,53 @@
 package storage
 
 import (
+	"time"
+
 	"github.com/jmoiron/sqlx"
 )
 
+const (
+	// RoleAdmin manage users and configuration
+	RoleAdmin = "admin"
+	// RoleOperator manage devices and send downlinks
+	RoleOperator = "operator"
+	// RoleViewer read only
+	RoleViewer = "viewer"
+)
+
 type User struct {
-	UserName     string `db:"user_name"`
-	PasswordHash string `db:"password_hash"`
+	UserName           string     `db:"user_name" json:"user_name"`
+	PasswordHash       string     `db:"password_hash" json:"-"`
+	Role               string     `db:"role" json:"role"`
+	Disabled           bool       `db:"disabled" json:"disabled"`
+	FailedLogins       int        `db:"failed_logins" json:"failed_logins"` // 连续登陆失败次数
+	LockedUntil        *time.Time `db:"locked_until" json:"locked_until"`   // 登陆失败过多锁定至
+	LastLoginAt        *time.Time `db:"last_login_at" json:"last_login_at"`
+	MustChangePassword bool       `db:"must_change_password" json:"must_change_password"` // 登陆后须先修改密码
+	CreatedAt          time.Time  `db:"created_at" json:"created_at"`
 }
 
+// Locked returns whether user is locked at t
+func (u User) Locked(t time.Time) bool {
+	return u.LockedUntil != nil && u.LockedUntil.After(t)
+}
+
+// userColumns columns of user model
+const userColumns = `user_name,
+		password_hash,
+		role,
+		disabled,
+		failed_logins,
+		locked_until,
+		last_login_at,
+		must_change_password,
+		created_at`
+
 // LoginUser check user name and password
 func LoginUser(userName string) (User, error) {
 	var usr User
 	err := sqlx.Get(db, &usr, `
-		select user_name,
-		password_hash
+		select `+userColumns+`
 		from users
 		where user_name=$1`,
 		userName,
@@ -25,14 +58,33 @@ func LoginUser(userName string) (User, error) {
 	return usr, nil
 }
 
-// UpdateUserPassword update user's password
+// GetUsers get all users order by user name
+func GetUsers() ([]User, error) {
+	users := []User{}
+	err := sqlx.Select(db, &users, `
+		select `+userColumns+`
+		from users
+		order by user_name`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// UpdateUserPassword update user's password and whether it must be changed on next login,
+// login lock is cleared
 func UpdateUserPassword(usr User) error {
 	_, err := db.Exec(`
 		update users
-		set password_hash=$2
+		set password_hash=$2,
+		must_change_password=$3,
+		failed_logins=0,
+		locked_until=null
 		where user_name=$1`,
 		usr.UserName,
 		usr.PasswordHash,
+		usr.MustChangePassword,
 	)
 	if err != nil {
 		return err
@@ -46,10 +98,95 @@ func CreateUser(usr User) error {
 	_, err := db.Exec(`
 		insert into users(
 			user_name,
-			password_hash
-		)values($1,$2)`,
+			password_hash,
+			role,
+			must_change_password,
+			created_at
+		)values($1,$2,$3,$4,$5)`,
 		usr.UserName,
 		usr.PasswordHash,
+		usr.Role,
+		usr.MustChangePassword,
+		time.Now(),
+	)
+	return err
+}
+
+// UpdateUser update user's role and disabled state
+func UpdateUser(usr User) error {
+	_, err := db.Exec(`
+		update users
+		set role=$2,
+		disabled=$3
+		where user_name=$1`,
+		usr.UserName,
+		usr.Role,
+		usr.Disabled,
+	)
+	return err
+}
+
+// UnlockUser clear failed logins and lock
+func UnlockUser(userName string) error {
+	_, err := db.Exec(`
+		update users
+		set failed_logins=0,
+		locked_until=null
+		where user_name=$1`,
+		userName,
+	)
+	return err
+}
+
+// DeleteUser delete user
+func DeleteUser(userName string) error {
+	_, err := db.Exec(`
+		delete from users
+		where user_name=$1`,
+		userName,
+	)
+	return err
+}
+
+// CountActiveAdmins count enabled admins except user
+func CountActiveAdmins(except string) (int, error) {
+	var count int
+	err := sqlx.Get(db, &count, `
+		select count(*)
+		from users
+		where role=$1 and not disabled and user_name<>$2`,
+		RoleAdmin,
+		except,
+	)
+	return count, err
+}
+
+// RecordLoginSuccess set last login time and clear failed logins
+func RecordLoginSuccess(userName string, t time.Time) error {
+	_, err := db.Exec(`
+		update users
+		set last_login_at=$2,
+		failed_logins=0,
+		locked_until=null
+		where user_name=$1`,
+		userName,
+		t,
+	)
+	return err
+}
+
+// RecordLoginFailure increase failed logins, user is locked until t+lockFor
+// and failed logins restart from 0 when it reaches maxAttempts
+func RecordLoginFailure(userName string, t time.Time, maxAttempts int, lockFor time.Duration) error {
+	_, err := db.Exec(`
+		update users
+		set failed_logins=case when failed_logins+1>=$3 then 0 else failed_logins+1 end,
+		locked_until=case when failed_logins+1>=$3 then $2::timestamptz + make_interval(secs => $4) else locked_until end
+		where user_name=$1`,
+		userName,
+		t,
+		maxAttempts,
+		lockFor.Seconds(),
 	)
 	return err
 }